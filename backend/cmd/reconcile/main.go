@@ -0,0 +1,67 @@
+// Command reconcile recomputes the event and tier seat counters from the
+// registrations table and reports every counter that had drifted.
+//
+// Run from the backend/ directory (reads the same .env as the server):
+//
+//	go run ./cmd/reconcile -dry-run   # report only
+//	go run ./cmd/reconcile            # report and fix
+//
+// It takes the same per-event lock as bookings. With LOCK_BACKEND=memory that
+// lock is local to this process, so fix mode should only run while the server
+// is stopped; dry runs are always safe.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/database"
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report drift without changing any counter")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)}) // keep SQL out of the report
+	locker, err := locking.FromEnv(db)
+	if err != nil {
+		log.Fatalf("Failed to configure lock: %v", err)
+	}
+	rec := services.NewSeatReconciler(db,
+		repositories.NewRegistrationRepository(db), repositories.NewEventRepository(db), locker)
+
+	report, err := rec.Reconcile(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("Reconcile failed: %v", err)
+	}
+	for _, d := range report.Drift {
+		where := "event"
+		if d.TierID != nil {
+			where = fmt.Sprintf("tier %q", d.TierName)
+		}
+		fmt.Printf("%s  %-30.30s  %-16s stored=%-5d actual=%-5d (%+d)\n",
+			d.EventID, d.Title, where, d.Stored, d.Actual, d.Actual-d.Stored)
+	}
+	verb := "fixed"
+	if *dryRun {
+		verb = "found (dry run, nothing changed)"
+	}
+	fmt.Printf("%d events checked, %d drifted counters %s\n", report.EventsChecked, len(report.Drift), verb)
+}
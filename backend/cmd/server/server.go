@@ -4,39 +4,85 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/Amrutavarshini24/Eventregistration/internal/config"
 	"github.com/Amrutavarshini24/Eventregistration/internal/handlers"
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
 	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
 	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
 	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+	"github.com/Amrutavarshini24/Eventregistration/internal/storage"
 )
 
 type Server struct {
-	engine *gin.Engine
-	port   string
+	engine  *gin.Engine
+	port    string
+	workers []func(ctx context.Context) // background jobs started by Run, stopped on shutdown
 }
 
-func New(db *gorm.DB) *Server {
+func New(db *gorm.DB) (*Server, error) {
+	// ── Layer 1 booking lock (LOCK_BACKEND, LOCK_TIMEOUT) ────────────────────
+	locker, err := locking.FromEnv(db)
+	if err != nil {
+		return nil, err
+	}
+	// Uploaded event images (MEDIA_DIR)
+	blobs, err := storage.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	// ── Repositories ─────────────────────────────────────────────────────────
-	userRepo  := repositories.NewUserRepository(db)
-	eventRepo := repositories.NewEventRepository(db)
-	regRepo   := repositories.NewRegistrationRepository(db)
+	userRepo   := repositories.NewUserRepository(db)
+	eventRepo  := repositories.NewEventRepository(db)
+	regRepo    := repositories.NewRegistrationRepository(db)
+	idemRepo   := repositories.NewIdempotencyRepository(db)
+	taxRepo    := repositories.NewTaxonomyRepository(db)
+	venueRepo  := repositories.NewVenueRepository(db)
+	mediaRepo  := repositories.NewMediaRepository(db)
+	statsRepo  := repositories.NewStatsRepository(db)
+	tplRepo    := repositories.NewTemplateRepository(db)
+	collabRepo := repositories.NewCollaboratorRepository(db)
 
 	// ── Services ─────────────────────────────────────────────────────────────
 	authSvc    := services.NewAuthService(userRepo)
-	eventSvc   := services.NewEventService(eventRepo)
-	bookingSvc := services.NewBookingService(db, regRepo, eventRepo)
+	eventSvc   := services.NewEventService(db, eventRepo, regRepo, taxRepo, venueRepo, tplRepo, locker, notify.LogNotifier{})
+	taxSvc     := services.NewTaxonomyService(taxRepo)
+	venueSvc   := services.NewVenueService(venueRepo)
+	mediaSvc   := services.NewMediaService(db, eventRepo, mediaRepo, blobs, locker)
+	statsSvc   := services.NewStatsService(eventRepo, statsRepo)
+	tplSvc     := services.NewTemplateService(tplRepo, eventRepo)
+	collabSvc  := services.NewCollaboratorService(eventRepo, collabRepo, userRepo, notify.LogNotifier{})
+	bookingSvc := services.NewBookingService(db, regRepo, eventRepo, userRepo, locker)
+	ticketSvc  := services.NewTicketService(db, regRepo, eventRepo)
+	reconciler := services.NewSeatReconciler(db, regRepo, eventRepo, locker)
 
 	// ── Handlers ─────────────────────────────────────────────────────────────
 	authH    := handlers.NewAuthHandler(authSvc)
 	eventH   := handlers.NewEventHandler(eventSvc)
 	bookingH := handlers.NewBookingHandler(bookingSvc)
+	ticketH  := handlers.NewTicketHandler(ticketSvc)
+	taxH     := handlers.NewTaxonomyHandler(taxSvc)
+	venueH   := handlers.NewVenueHandler(venueSvc)
+	mediaH   := handlers.NewMediaHandler(mediaSvc)
+	statsH   := handlers.NewStatsHandler(statsSvc)
+	tplH     := handlers.NewTemplateHandler(tplSvc)
+	collabH  := handlers.NewCollaboratorHandler(collabSvc)
 
 	// ── Gin engine ───────────────────────────────────────────────────────────
 	if os.Getenv("APP_ENV") == "production" {
@@ -45,6 +91,8 @@ func New(db *gorm.DB) *Server {
 	engine := gin.New()
 	engine.Use(gin.Logger())
 	engine.Use(gin.Recovery())
+	// REQUEST_TIMEOUT bounds every request's context (lock waits, queries).
+	engine.Use(middleware.Timeout(config.Duration("REQUEST_TIMEOUT", 10*time.Second)))
 
 	// ── CORS middleware ───────────────────────────────────────────────────────
 	// Reads CORS_ORIGINS from .env (comma-separated).
@@ -68,38 +116,198 @@ func New(db *gorm.DB) *Server {
 	auth.POST("/register", authH.Register)
 	auth.POST("/login",    authH.Login)
 
-	// Events
+	// Categories and tags
+	api.GET("/categories", taxH.ListCategories)
+	api.POST("/categories",
+		middleware.AuthRequired(),
+		middleware.OrganizerRequired(),
+		taxH.CreateCategory,
+	)
+
+	// Venues
+	venues := api.Group("/venues")
+	venues.GET("",     venueH.ListVenues)
+	venues.GET("/:id", venueH.GetVenue)
+	venues.POST("",
+		middleware.AuthRequired(),
+		middleware.OrganizerRequired(),
+		venueH.CreateVenue,
+	)
+
+	// Event templates (organizer's own)
+	tpls := api.Group("/templates", middleware.AuthRequired(), middleware.OrganizerRequired())
+	tpls.GET("",        tplH.ListTemplates)
+	tpls.GET("/:id",    tplH.GetTemplate)
+	tpls.POST("",       tplH.CreateTemplate)
+	tpls.DELETE("/:id", tplH.DeleteTemplate)
+
+	// Events. Routes on an existing event only need a login: the services
+	// check the caller's role on that event.
 	evts := api.Group("/events")
-	evts.GET("",     eventH.ListEvents)
-	evts.GET("/:id", eventH.GetEvent)
+	evts.GET("",        eventH.ListEvents)
+	evts.GET("/search", eventH.SearchEvents)
+	evts.GET("/:id",    eventH.GetEvent)
+	idempotent := middleware.Idempotency(idemRepo)
 	evts.POST("",
 		middleware.AuthRequired(),
 		middleware.OrganizerRequired(),
+		idempotent,
 		eventH.CreateEvent,
 	)
+	evts.PATCH("/:id",
+		middleware.AuthRequired(),
+		eventH.UpdateEvent,
+	)
+	evts.DELETE("/:id",
+		middleware.AuthRequired(),
+		eventH.DeleteEvent,
+	)
+	evts.POST("/:id/publish",
+		middleware.AuthRequired(),
+		eventH.PublishEvent,
+	)
+	evts.POST("/:id/clone",
+		middleware.AuthRequired(),
+		middleware.OrganizerRequired(),
+		idempotent,
+		eventH.CloneEvent,
+	)
+	evts.POST("/:id/cancel",
+		middleware.AuthRequired(),
+		eventH.CancelEvent,
+	)
+	evts.GET("/:id/media/:mediaId/:variant", mediaH.GetMedia)
+	evts.POST("/:id/media",
+		middleware.AuthRequired(),
+		mediaH.UploadMedia,
+	)
+	evts.DELETE("/:id/media/:mediaId",
+		middleware.AuthRequired(),
+		mediaH.DeleteMedia,
+	)
 	evts.POST("/:id/register",
 		middleware.AuthRequired(),
+		idempotent,
 		bookingH.BookEvent,
 	)
+	evts.DELETE("/:id/register",
+		middleware.AuthRequired(),
+		bookingH.CancelBooking,
+	)
+	evts.POST("/:id/hold",
+		middleware.AuthRequired(),
+		idempotent,
+		bookingH.HoldSeats,
+	)
+	evts.POST("/:id/checkin",
+		middleware.AuthRequired(),
+		ticketH.CheckIn,
+	)
+	evts.POST("/:id/no-shows",
+		middleware.AuthRequired(),
+		ticketH.MarkNoShows,
+	)
 	evts.GET("/:id/registrations",
 		middleware.AuthRequired(),
 		bookingH.GetEventRegistrations,
 	)
+	evts.GET("/:id/stats",
+		middleware.AuthRequired(),
+		statsH.EventStats,
+	)
+	collabs := evts.Group("/:id/collaborators", middleware.AuthRequired())
+	collabs.GET("",            collabH.ListCollaborators)
+	collabs.POST("",           collabH.InviteCollaborator)
+	collabs.DELETE("/:userId", collabH.RemoveCollaborator)
+
+	// Registrations (owner only — checked in the service)
+	regs := api.Group("/registrations", middleware.AuthRequired())
+	regs.POST("/:id/confirm", bookingH.ConfirmHold)
+	regs.DELETE("/:id/hold",  bookingH.ReleaseHold)
+	regs.POST("/:id/transfer", bookingH.TransferRegistration)
+	regs.GET("/:id/ticket",     ticketH.GetTicket)
+	regs.GET("/:id/ticket.png", ticketH.GetTicketQR)
+	regs.POST("/:id/refund", bookingH.RefundRegistration)
+
+	// Organizer dashboard
+	api.GET("/organizer/stats",
+		middleware.AuthRequired(),
+		middleware.OrganizerRequired(),
+		statsH.OrganizerStats,
+	)
 
 	// Me
 	me := api.Group("/me", middleware.AuthRequired())
 	me.GET("/registrations", bookingH.GetMyRegistrations)
+	me.GET("/collaborations", collabH.MyCollaborations)
+
+	// ── Background workers ───────────────────────────────────────────────────
+	// HOLD_SWEEP_INTERVAL: how often expired seat holds are released.
+	holdSweep := config.Duration("HOLD_SWEEP_INTERVAL", 30*time.Second)
+	workers := []func(ctx context.Context){
+		func(ctx context.Context) { bookingSvc.RunHoldSweeper(ctx, holdSweep) },
+		func(ctx context.Context) {
+			every(ctx, time.Hour, "idempotency cleanup", func() error {
+				_, err := idemRepo.DeleteExpired(ctx, time.Now())
+				return err
+			})
+		},
+	}
+
+	// SEAT_RECONCILE_INTERVAL: how often seat counters are recomputed from
+	// registrations and corrected. Off unless set.
+	if interval := config.Duration("SEAT_RECONCILE_INTERVAL", 0); interval > 0 {
+		workers = append(workers, func(ctx context.Context) {
+			every(ctx, interval, "seat reconciler", func() error {
+				_, err := reconciler.Reconcile(ctx, false)
+				return err
+			})
+		})
+	}
 
 	port := os.Getenv("APP_PORT")
 	if port == "" {
 		port = "8080"
 	}
-	return &Server{engine: engine, port: port}
+	return &Server{engine: engine, port: port, workers: workers}, nil
 }
 
+// Run serves until SIGINT or SIGTERM, then stops the background workers and
+// gives in-flight requests up to SHUTDOWN_TIMEOUT (default 15s) to finish.
 func (s *Server) Run() error {
-	addr := fmt.Sprintf(":%s", s.port)
-	return s.engine.Run(addr)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, w := range s.workers {
+		go w(ctx)
+	}
+	// Requests get their own base context, cancelled only once Shutdown has
+	// drained them or given up, so a signal doesn't abort them mid-booking.
+	reqCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	srv := &http.Server{
+		Addr:        fmt.Sprintf(":%s", s.port),
+		Handler:     s.engine,
+		BaseContext: func(net.Listener) context.Context { return reqCtx },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	log.Printf("listening on %s", srv.Addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	log.Printf("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Duration("SHUTDOWN_TIMEOUT", 15*time.Second))
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	cancelRequests() // abort whatever outlived SHUTDOWN_TIMEOUT
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 // corsMiddleware adds CORS headers for cross-origin requests from the frontend.
@@ -116,8 +324,9 @@ func corsMiddleware(origins string) gin.HandlerFunc {
 			}
 		}
 		c.Header("Access-Control-Allow-Origin",  allow)
-		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Authorization,Content-Type")
+		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Authorization,Content-Type,Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "Idempotent-Replayed")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -125,3 +334,19 @@ func corsMiddleware(origins string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// every runs fn each interval until ctx is done, logging failures under name.
+func every(ctx context.Context, interval time.Duration, name string, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(); err != nil && ctx.Err() == nil {
+				log.Printf("%s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Package config reads typed settings from the environment.
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Duration reads a Go duration (e.g. "30s") from key, falling back to def
+// when it is unset or invalid.
+func Duration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		log.Printf("ignoring invalid %s=%q", key, v)
+	}
+	return def
+}
+
+// StrictDuration reads a positive Go duration from key, falling back to def
+// when it is unset. Unlike Duration, an invalid value is an error.
+func StrictDuration(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return d, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/glebarez/sqlite"
@@ -45,13 +46,95 @@ func Connect() (*gorm.DB, error) {
 // Migrate auto-migrates all models.
 func Migrate(db *gorm.DB) error {
 	log.Println("Running migrations…")
-	if err := db.AutoMigrate(&models.User{}, &models.Event{}, &models.Registration{}); err != nil {
+	if err := db.AutoMigrate(
+		&models.User{}, &models.Category{}, &models.Tag{}, &models.Venue{}, &models.Event{}, &models.EventMedia{}, &models.TicketTier{},
+		&models.EventTemplate{}, &models.TemplateTier{}, &models.EventCollaborator{},
+		&models.Registration{}, &models.RegistrationAttendee{}, &models.RegistrationTransfer{},
+		&models.RegistrationStatusChange{},
+		&models.IdempotencyKey{},
+	); err != nil {
+		return fmt.Errorf("database.Migrate: %w", err)
+	}
+	if err := migrateRegistrationIndexes(db); err != nil {
+		return fmt.Errorf("database.Migrate: %w", err)
+	}
+	if err := migrateEventSearch(db); err != nil {
 		return fmt.Errorf("database.Migrate: %w", err)
 	}
 	log.Println("Migrations complete.")
 	return nil
 }
 
+// migrateRegistrationIndexes replaces the legacy (user_id, event_id, status)
+// unique index — which rejected a second cancelled row for the same user and
+// event — with a partial unique index covering only active registrations.
+// The index is rebuilt on every run so it follows models.ActiveStatuses.
+func migrateRegistrationIndexes(db *gorm.DB) error {
+	m := db.Migrator()
+	if m.HasIndex(&models.Registration{}, "idx_user_event_status") {
+		if err := m.DropIndex(&models.Registration{}, "idx_user_event_status"); err != nil {
+			return fmt.Errorf("drop idx_user_event_status: %w", err)
+		}
+	}
+	statuses := make([]string, len(models.ActiveStatuses))
+	for i, st := range models.ActiveStatuses {
+		statuses[i] = fmt.Sprintf("'%s'", st)
+	}
+	stmts := []string{
+		"DROP INDEX IF EXISTS idx_user_event_active",
+		fmt.Sprintf("CREATE UNIQUE INDEX idx_user_event_active ON registrations (user_id, event_id) WHERE status IN (%s)",
+			strings.Join(statuses, ", ")),
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("idx_user_event_active: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateEventSearch sets up full-text search over event titles and
+// descriptions for repositories.EventRepository.Search. Postgres gets a
+// generated, weighted tsvector column with a GIN index. SQLite gets an FTS5
+// table over the events rows, kept in sync by triggers and rebuilt on every
+// run, since AutoMigrate may recreate the events table and renumber rowids.
+func migrateEventSearch(db *gorm.DB) error {
+	var stmts []string
+	switch db.Dialector.Name() {
+	case "postgres":
+		stmts = []string{
+			`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED`,
+			"CREATE INDEX IF NOT EXISTS idx_events_search ON events USING GIN (search_vector)",
+		}
+	case "sqlite":
+		stmts = []string{
+			`CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(
+				title, description, content='events', content_rowid='rowid', tokenize='porter unicode61')`,
+			`CREATE TRIGGER IF NOT EXISTS events_fts_ai AFTER INSERT ON events BEGIN
+				INSERT INTO events_fts(rowid, title, description) VALUES (new.rowid, new.title, new.description);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS events_fts_ad AFTER DELETE ON events BEGIN
+				INSERT INTO events_fts(events_fts, rowid, title, description) VALUES ('delete', old.rowid, old.title, old.description);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS events_fts_au AFTER UPDATE OF title, description ON events BEGIN
+				INSERT INTO events_fts(events_fts, rowid, title, description) VALUES ('delete', old.rowid, old.title, old.description);
+				INSERT INTO events_fts(rowid, title, description) VALUES (new.rowid, new.title, new.description);
+			END`,
+			"INSERT INTO events_fts(events_fts) VALUES ('rebuild')",
+		}
+	default:
+		return fmt.Errorf("event search: unsupported dialect %s", db.Dialector.Name())
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("event search: %w", err)
+		}
+	}
+	return nil
+}
+
 func postgresDSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s client_encoding=UTF8",
 		env("DB_HOST", "localhost"),
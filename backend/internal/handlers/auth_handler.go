@@ -19,13 +19,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	user, err := h.svc.Register(&req)
+	user, err := h.svc.Register(c.Request.Context(), &req)
 	if err != nil {
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
 	// Auto-login: generate token
-	token, _, _ := h.svc.Login(&models.LoginRequest{Email: req.Email, Password: req.Password})
+	token, _, _ := h.svc.Login(c.Request.Context(), &models.LoginRequest{Email: req.Email, Password: req.Password})
 	c.JSON(http.StatusCreated, models.AuthResponse{Token: token, User: user})
 }
 
@@ -36,7 +36,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	token, user, err := h.svc.Login(&req)
+	token, user, err := h.svc.Login(c.Request.Context(), &req)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
 	"github.com/Amrutavarshini24/Eventregistration/internal/services"
 )
 
@@ -15,27 +17,114 @@ func NewBookingHandler(s services.BookingService) *BookingHandler { return &Book
 
 // POST /api/events/:id/register
 func (h *BookingHandler) BookEvent(c *gin.Context) {
+	req, ok := bindBookingRequest(c)
+	if !ok {
+		return
+	}
 	uid, _ := c.Get(middleware.ContextKeyUserID)
-	reg, err := h.svc.Book(uid.(string), c.Param("id"))
+	reg, err := h.svc.Book(c.Request.Context(), uid.(string), c.Param("id"), req)
 	if err != nil {
-		switch {
-		case errors.Is(err, services.ErrEventFull):
-			c.JSON(http.StatusConflict, gin.H{"error": "event is fully booked"})
-		case errors.Is(err, services.ErrDuplicateBooking):
-			c.JSON(http.StatusConflict, gin.H{"error": "you have already registered for this event"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		writeBookingError(c, err)
+		return
+	}
+	if reg.Status == models.StatusWaitlisted {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Event is full — you have been added to the waitlist", "registration": reg,
+		})
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"message": "Seat reserved successfully", "registration": reg})
 }
 
-// GET /api/events/:id/registrations
+// DELETE /api/events/:id/register
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.Cancel(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrRegistrationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "you have no active registration for this event"})
+			return
+		}
+		writeBookingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Registration cancelled", "registration": reg})
+}
+
+// POST /api/events/:id/hold
+func (h *BookingHandler) HoldSeats(c *gin.Context) {
+	req, ok := bindBookingRequest(c)
+	if !ok {
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.Hold(c.Request.Context(), uid.(string), c.Param("id"), req)
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Seats held — confirm before the hold expires", "registration": reg})
+}
+
+// POST /api/registrations/:id/confirm
+func (h *BookingHandler) ConfirmHold(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.ConfirmHold(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Booking confirmed", "registration": reg})
+}
+
+// DELETE /api/registrations/:id/hold
+func (h *BookingHandler) ReleaseHold(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.ReleaseHold(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Hold released", "registration": reg})
+}
+
+// POST /api/registrations/:id/transfer
+func (h *BookingHandler) TransferRegistration(c *gin.Context) {
+	var req models.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.Transfer(c.Request.Context(), uid.(string), c.Param("id"), req.Email)
+	if err != nil {
+		if errors.Is(err, services.ErrDuplicateBooking) {
+			c.JSON(http.StatusConflict, gin.H{"error": "the recipient is already registered for this event"})
+			return
+		}
+		writeBookingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Registration transferred", "registration": reg})
+}
+
+// POST /api/registrations/:id/refund  (organizer or co-organizer)
+func (h *BookingHandler) RefundRegistration(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.Refund(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeBookingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Registration refunded", "registration": reg})
+}
+
+// GET /api/events/:id/registrations  (organizer or staff)
 func (h *BookingHandler) GetEventRegistrations(c *gin.Context) {
-	regs, err := h.svc.GetEventRegistrations(c.Param("id"))
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	regs, err := h.svc.GetEventRegistrations(c.Request.Context(), uid.(string), c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeBookingError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"registrations": regs, "count": len(regs)})
@@ -44,10 +133,58 @@ func (h *BookingHandler) GetEventRegistrations(c *gin.Context) {
 // GET /api/me/registrations
 func (h *BookingHandler) GetMyRegistrations(c *gin.Context) {
 	uid, _ := c.Get(middleware.ContextKeyUserID)
-	regs, err := h.svc.GetUserRegistrations(uid.(string))
+	regs, err := h.svc.GetUserRegistrations(c.Request.Context(), uid.(string))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"registrations": regs, "count": len(regs)})
 }
+
+// bindBookingRequest reads the optional booking body; an empty POST books a
+// single seat. It writes the 400 itself and returns false on bad input.
+func bindBookingRequest(c *gin.Context) (*models.BookingRequest, bool) {
+	var req models.BookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &req, true
+}
+
+// writeBookingError maps BookingService sentinel errors to HTTP statuses.
+func writeBookingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventFull):
+		c.JSON(http.StatusConflict, gin.H{"error": "event is fully booked"})
+	case errors.Is(err, services.ErrDuplicateBooking):
+		c.JSON(http.StatusConflict, gin.H{"error": "you have already registered for this event"})
+	case errors.Is(err, services.ErrTierSoldOut):
+		c.JSON(http.StatusConflict, gin.H{"error": "this ticket tier is sold out"})
+	case errors.Is(err, services.ErrEventNotPublished):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEventCancelled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSalesClosed):
+		c.JSON(http.StatusForbidden, gin.H{"error": "ticket sales are not open for this event"})
+	case errors.Is(err, services.ErrTierNotOnSale):
+		c.JSON(http.StatusForbidden, gin.H{"error": "this ticket tier is not on sale"})
+	case errors.Is(err, services.ErrNotHeld), errors.Is(err, services.ErrHoldExpired),
+		errors.Is(err, services.ErrAlreadyCheckedIn), errors.Is(err, services.ErrNotTransferable),
+		errors.Is(err, services.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEventNotFound), errors.Is(err, services.ErrTierNotFound),
+		errors.Is(err, services.ErrRegistrationNotFound), errors.Is(err, services.ErrRecipientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidQuantity), errors.Is(err, services.ErrTierRequired),
+		errors.Is(err, services.ErrSelfTransfer):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTimeout):
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type CollaboratorHandler struct{ svc services.CollaboratorService }
+
+func NewCollaboratorHandler(s services.CollaboratorService) *CollaboratorHandler {
+	return &CollaboratorHandler{svc: s}
+}
+
+// GET /api/events/:id/collaborators  (any collaborator)
+func (h *CollaboratorHandler) ListCollaborators(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	cs, err := h.svc.List(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeCollaboratorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"collaborators": cs, "count": len(cs)})
+}
+
+// POST /api/events/:id/collaborators  (organizer or co-organizer)
+func (h *CollaboratorHandler) InviteCollaborator(c *gin.Context) {
+	var req models.InviteCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	col, err := h.svc.Invite(c.Request.Context(), uid.(string), c.Param("id"), &req)
+	if err != nil {
+		writeCollaboratorError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, col)
+}
+
+// DELETE /api/events/:id/collaborators/:userId  (organizer or co-organizer,
+// or the collaborator themselves)
+func (h *CollaboratorHandler) RemoveCollaborator(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	if err := h.svc.Remove(c.Request.Context(), uid.(string), c.Param("id"), c.Param("userId")); err != nil {
+		writeCollaboratorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Collaborator removed"})
+}
+
+// GET /api/me/collaborations
+func (h *CollaboratorHandler) MyCollaborations(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	cs, err := h.svc.Collaborations(c.Request.Context(), uid.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"collaborations": cs, "count": len(cs)})
+}
+
+func writeCollaboratorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound), errors.Is(err, services.ErrInviteeNotFound),
+		errors.Is(err, services.ErrCollaboratorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInviteOrganizer):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
@@ -21,30 +24,164 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 		return
 	}
 	id, _ := c.Get(middleware.ContextKeyUserID)
-	ev, err := h.svc.CreateEvent(&req, id.(string))
+	ev, err := h.svc.CreateEvent(c.Request.Context(), &req, id.(string))
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, ev)
+}
+
+// POST /api/events/:id/clone  (organizer or co-organizer)
+func (h *EventHandler) CloneEvent(c *gin.Context) {
+	var req models.CloneEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	ev, err := h.svc.CloneEvent(c.Request.Context(), uid.(string), c.Param("id"), &req)
+	if err != nil {
+		writeEventError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, ev)
 }
 
-// GET /api/events
+// GET /api/events?from=&to=&organizer_id=&available=&upcoming=&sort=&limit=&offset=
 func (h *EventHandler) ListEvents(c *gin.Context) {
-	evs, err := h.svc.ListEvents()
+	var q models.EventListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := h.svc.ListEvents(c.Request.Context(), &q)
+	if errors.Is(err, services.ErrInvalidNear) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if next := page.Offset + page.Limit; int64(next) < page.Total {
+		page.Next = pageURL(c, next, page.Limit)
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GET /api/events/search?q=&limit=&offset=
+func (h *EventHandler) SearchEvents(c *gin.Context) {
+	var q models.EventSearchQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := h.svc.SearchEvents(c.Request.Context(), &q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"events": evs, "count": len(evs)})
+	if next := page.Offset + page.Limit; int64(next) < page.Total {
+		page.Next = pageURL(c, next, page.Limit)
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// pageURL is the current request's path and query with offset and limit
+// replaced, so the other filters carry over to the next page.
+func pageURL(c *gin.Context, offset, limit int) string {
+	u := *c.Request.URL
+	v := u.Query()
+	v.Set("offset", strconv.Itoa(offset))
+	v.Set("limit", strconv.Itoa(limit))
+	u.RawQuery = v.Encode()
+	return u.RequestURI()
 }
 
 // GET /api/events/:id
 func (h *EventHandler) GetEvent(c *gin.Context) {
-	ev, err := h.svc.GetEvent(c.Param("id"))
+	ev, err := h.svc.GetEvent(c.Request.Context(), c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
 		return
 	}
 	c.JSON(http.StatusOK, ev)
 }
+
+// PATCH /api/events/:id  (organizer or co-organizer)
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	var req models.UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	ev, err := h.svc.UpdateEvent(c.Request.Context(), uid.(string), c.Param("id"), &req)
+	if err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ev)
+}
+
+// DELETE /api/events/:id  (event organizer)
+func (h *EventHandler) DeleteEvent(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	if err := h.svc.DeleteEvent(c.Request.Context(), uid.(string), c.Param("id")); err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Event deleted"})
+}
+
+// POST /api/events/:id/publish  (organizer or co-organizer)
+func (h *EventHandler) PublishEvent(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	ev, err := h.svc.PublishEvent(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ev)
+}
+
+// POST /api/events/:id/cancel  (organizer or co-organizer)
+func (h *EventHandler) CancelEvent(c *gin.Context) {
+	var req models.CancelEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	ev, n, err := h.svc.CancelEvent(c.Request.Context(), uid.(string), c.Param("id"), req.Reason)
+	if err != nil {
+		writeEventError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Event cancelled", "event": ev, "registrations_cancelled": n})
+}
+
+// writeEventError maps EventService errors to HTTP statuses. Anything not
+// listed is a server fault: it is logged and answered with a bare 500.
+func writeEventError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound), errors.Is(err, services.ErrTemplateNotFound),
+		errors.Is(err, services.ErrVenueNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCapacityBelowRegistered), errors.Is(err, services.ErrEventHasRegistrations),
+		errors.Is(err, services.ErrEventCancelled), errors.Is(err, services.ErrInvalidEventTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTimeout):
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidEvent), errors.Is(err, services.ErrEndBeforeStart),
+		errors.Is(err, services.ErrNonexistentTime), errors.Is(err, services.ErrAmbiguousTime),
+		errors.Is(err, services.ErrInvalidTimezone), errors.Is(err, services.ErrUnknownCategory):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
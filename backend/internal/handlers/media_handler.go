@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type MediaHandler struct{ svc services.MediaService }
+
+func NewMediaHandler(s services.MediaService) *MediaHandler { return &MediaHandler{svc: s} }
+
+// multipartOverhead is the room left for multipart headers and the kind
+// field on top of the file size limit.
+const multipartOverhead = 1 << 20
+
+// POST /api/events/:id/media  (organizer or co-organizer; multipart: file, kind=cover|gallery)
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.svc.MaxBytes()+multipartOverhead)
+	// FormFile parses the body, so it goes first: reading kind first would
+	// parse it and swallow an oversize error.
+	fh, err := c.FormFile("file")
+	var tooBig *http.MaxBytesError
+	if errors.As(err, &tooBig) {
+		writeMediaError(c, services.ErrMediaTooLarge)
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	kind := models.MediaKind(c.DefaultPostForm("kind", string(models.MediaGallery)))
+	if kind != models.MediaCover && kind != models.MediaGallery {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be cover or gallery"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	m, err := h.svc.Upload(c.Request.Context(), uid.(string), c.Param("id"), kind, f)
+	if err != nil {
+		writeMediaError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, m)
+}
+
+// DELETE /api/events/:id/media/:mediaId  (organizer or co-organizer)
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	if err := h.svc.Delete(c.Request.Context(), uid.(string), c.Param("id"), c.Param("mediaId")); err != nil {
+		writeMediaError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Image deleted"})
+}
+
+// GET /api/events/:id/media/:mediaId/:variant  (original, medium or thumb)
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	rc, ct, err := h.svc.Open(c.Request.Context(), c.Param("id"), c.Param("mediaId"), c.Param("variant"))
+	if err != nil {
+		writeMediaError(c, err)
+		return
+	}
+	defer rc.Close()
+	// Files are never rewritten: a new upload gets a new media ID.
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Type", ct)
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, rc)
+}
+
+func writeMediaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrMediaNotFound), errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMediaTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnsupportedMedia):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrGalleryFull):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTimeout):
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type StatsHandler struct{ svc services.StatsService }
+
+func NewStatsHandler(s services.StatsService) *StatsHandler { return &StatsHandler{svc: s} }
+
+// GET /api/organizer/stats?bucket=day|hour&from=&to=  (organizer)
+func (h *StatsHandler) OrganizerStats(c *gin.Context) {
+	var q models.StatsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	stats, err := h.svc.OrganizerStats(c.Request.Context(), uid.(string), &q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GET /api/events/:id/stats?bucket=day|hour&from=&to=  (any role on the event)
+func (h *StatsHandler) EventStats(c *gin.Context) {
+	var q models.StatsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	stats, err := h.svc.EventStats(c.Request.Context(), uid.(string), c.Param("id"), &q)
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, stats)
+	}
+}
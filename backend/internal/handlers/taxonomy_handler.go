@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type TaxonomyHandler struct{ svc services.TaxonomyService }
+
+func NewTaxonomyHandler(s services.TaxonomyService) *TaxonomyHandler { return &TaxonomyHandler{svc: s} }
+
+// GET /api/categories
+func (h *TaxonomyHandler) ListCategories(c *gin.Context) {
+	tax, err := h.svc.Taxonomy(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tax)
+}
+
+// POST /api/categories  (organizer)
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var req models.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	cat, err := h.svc.CreateCategory(c.Request.Context(), uid.(string), &req)
+	switch {
+	case errors.Is(err, services.ErrCategoryExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusCreated, cat)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type TemplateHandler struct{ svc services.TemplateService }
+
+func NewTemplateHandler(s services.TemplateService) *TemplateHandler { return &TemplateHandler{svc: s} }
+
+// GET /api/templates  (organizer; own templates only)
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	ts, err := h.svc.ListTemplates(c.Request.Context(), uid.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": ts, "count": len(ts)})
+}
+
+// GET /api/templates/:id  (organizer)
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	t, err := h.svc.GetTemplate(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// POST /api/templates  (organizer)
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	t, err := h.svc.CreateTemplate(c.Request.Context(), uid.(string), &req)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// DELETE /api/templates/:id  (organizer)
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	if err := h.svc.DeleteTemplate(c.Request.Context(), uid.(string), c.Param("id")); err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+func writeTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound), errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTemplateExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type TicketHandler struct{ svc services.TicketService }
+
+func NewTicketHandler(s services.TicketService) *TicketHandler { return &TicketHandler{svc: s} }
+
+// GET /api/registrations/:id/ticket
+func (h *TicketHandler) GetTicket(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	t, err := h.svc.Ticket(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeTicketError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// GET /api/registrations/:id/ticket.png?size=256
+func (h *TicketHandler) GetTicketQR(c *gin.Context) {
+	size, err := strconv.Atoi(c.DefaultQuery("size", "256"))
+	if err != nil || size < 64 || size > 1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size must be between 64 and 1024"})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	png, err := h.svc.TicketQR(c.Request.Context(), uid.(string), c.Param("id"), size)
+	if err != nil {
+		writeTicketError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// POST /api/events/:id/checkin  (organizer or staff)
+func (h *TicketHandler) CheckIn(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	reg, err := h.svc.CheckIn(c.Request.Context(), uid.(string), c.Param("id"), req.Token)
+	if err != nil {
+		writeTicketError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Checked in", "registration": reg})
+}
+
+// POST /api/events/:id/no-shows  (organizer or co-organizer)
+func (h *TicketHandler) MarkNoShows(c *gin.Context) {
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	n, err := h.svc.MarkNoShows(c.Request.Context(), uid.(string), c.Param("id"))
+	if err != nil {
+		writeTicketError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "No-shows marked", "count": n})
+}
+
+func writeTicketError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRegistrationNotFound), errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEventOrganizer):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyCheckedIn), errors.Is(err, services.ErrTicketUnavailable),
+		errors.Is(err, services.ErrEventNotStarted), errors.Is(err, services.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTicket):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+type VenueHandler struct{ svc services.VenueService }
+
+func NewVenueHandler(s services.VenueService) *VenueHandler { return &VenueHandler{svc: s} }
+
+// GET /api/venues
+func (h *VenueHandler) ListVenues(c *gin.Context) {
+	vs, err := h.svc.ListVenues(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"venues": vs, "count": len(vs)})
+}
+
+// GET /api/venues/:id
+func (h *VenueHandler) GetVenue(c *gin.Context) {
+	v, err := h.svc.GetVenue(c.Request.Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, services.ErrVenueNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, v)
+	}
+}
+
+// POST /api/venues  (organizer)
+func (h *VenueHandler) CreateVenue(c *gin.Context) {
+	var req models.CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, _ := c.Get(middleware.ContextKeyUserID)
+	v, err := h.svc.CreateVenue(c.Request.Context(), uid.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, v)
+}
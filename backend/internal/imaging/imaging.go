@@ -0,0 +1,137 @@
+// Package imaging checks uploaded images and makes downscaled copies of them
+// using only the standard library decoders and encoders (JPEG, PNG, GIF).
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	// ErrUnsupported is returned for content that isn't a JPEG, PNG or GIF
+	// image, whatever the client said it was.
+	ErrUnsupported = errors.New("unsupported image type (use JPEG, PNG or GIF)")
+	// ErrTooManyPixels guards against decompression bombs: small files that
+	// decode to huge bitmaps.
+	ErrTooManyPixels = errors.New("image dimensions too large")
+)
+
+// MaxPixels bounds width × height of images that are decoded.
+const MaxPixels = 40_000_000
+
+// Image is a decoded upload.
+type Image struct {
+	img image.Image
+	// ContentType is sniffed from the bytes: image/jpeg, image/png or
+	// image/gif.
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Decode sniffs data's type, checks its dimensions before decoding, and
+// decodes it. Only the first frame of an animated GIF is kept.
+func Decode(data []byte) (*Image, error) {
+	ct := http.DetectContentType(data)
+	switch ct {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, fmt.Errorf("%w: got %s", ErrUnsupported, ct)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	return &Image{img: img, ContentType: ct, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Fit returns a copy of im scaled down to fit within maxSide × maxSide,
+// keeping the aspect ratio, encoded as JPEG for JPEG sources and PNG
+// otherwise (to keep transparency). ok is false when im already fits, in
+// which case the original serves as the variant.
+func (im *Image) Fit(maxSide int) (data []byte, contentType string, ok bool, err error) {
+	w, h := im.Width, im.Height
+	if w <= maxSide && h <= maxSide {
+		return nil, "", false, nil
+	}
+	if w >= h {
+		w, h = maxSide, max(1, h*maxSide/w)
+	} else {
+		w, h = max(1, w*maxSide/h), maxSide
+	}
+	dst := downscale(im.img, w, h)
+
+	var buf bytes.Buffer
+	contentType = "image/png"
+	if im.ContentType == "image/jpeg" {
+		contentType = "image/jpeg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, "", false, fmt.Errorf("imaging.Fit: %w", err)
+	}
+	return buf.Bytes(), contentType, true, nil
+}
+
+// Ext is the file extension for an image content type.
+func Ext(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	}
+	return ""
+}
+
+// downscale shrinks src to w × h by averaging the source pixels each
+// destination pixel covers (a box filter), which avoids the aliasing of
+// nearest-neighbour sampling. Averaging is done on premultiplied RGBA so
+// transparent pixels don't bleed their colour.
+func downscale(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	sw, sh := b.Dx(), b.Dy()
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		y0, y1 := dy*sh/h, max((dy+1)*sh/h, dy*sh/h+1)
+		for dx := 0; dx < w; dx++ {
+			x0, x1 := dx*sw/w, max((dx+1)*sw/w, dx*sw/w+1)
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				row := rgba.Pix[y*rgba.Stride+x0*4 : y*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					bl += uint64(row[i+2])
+					a += uint64(row[i+3])
+					n++
+				}
+			}
+			o := dst.PixOffset(dx, dy)
+			dst.Pix[o], dst.Pix[o+1], dst.Pix[o+2], dst.Pix[o+3] =
+				uint8(r/n), uint8(g/n), uint8(bl/n), uint8(a/n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,74 @@
+// Package locking provides Layer 1 of the booking defence: a per-key lock
+// (one key per event) that serialises bookings for the same event.
+//
+// Three backends are available, picked by LOCK_BACKEND:
+//   - memory   — in-process, serialises goroutines on one node (default).
+//   - postgres — session-level advisory locks, serialise across nodes.
+//   - none     — no-op, for SQLite deployments where the single-writer
+//                database already serialises transactions.
+//
+// Every backend is only an optimisation on top of Layers 2 and 3; losing a
+// lock can cost throughput but never correctness.
+package locking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/config"
+)
+
+// ErrTimeout is returned when a lock is not acquired within the timeout or
+// before the caller's context deadline.
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// Locker serialises work on a key.
+type Locker interface {
+	// Lock blocks until key is held, the locker's timeout elapses or ctx
+	// ends. The returned func releases the lock; calling it more than once
+	// is a no-op.
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// FromEnv builds the Locker selected by LOCK_BACKEND (memory, postgres or
+// none; default memory) with an acquisition timeout of LOCK_TIMEOUT (a Go
+// duration, default 5s).
+func FromEnv(db *gorm.DB) (Locker, error) {
+	timeout, err := config.StrictDuration("LOCK_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("locking.FromEnv: %w", err)
+	}
+	switch backend := os.Getenv("LOCK_BACKEND"); backend {
+	case "", "memory":
+		return NewMemoryLocker(timeout), nil
+	case "postgres":
+		if db.Dialector.Name() != "postgres" {
+			return nil, fmt.Errorf("locking.FromEnv: LOCK_BACKEND=postgres needs a postgres database, got %s", db.Dialector.Name())
+		}
+		return NewPostgresLocker(db, timeout), nil
+	case "none":
+		return NopLocker{}, nil
+	default:
+		return nil, fmt.Errorf("locking.FromEnv: unknown LOCK_BACKEND %q", backend)
+	}
+}
+
+// NopLocker never blocks. It suits SQLite, whose single writer already
+// serialises the booking transactions.
+type NopLocker struct{}
+
+func (NopLocker) Lock(context.Context, string) (func(), error) { return func() {}, nil }
+
+// waitErr reports why a wait on ctx ended: ErrTimeout for a deadline,
+// otherwise the context's own error (e.g. the client went away).
+func waitErr(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return ctx.Err()
+}
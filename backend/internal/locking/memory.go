@@ -0,0 +1,62 @@
+package locking
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryLocker keeps one entry per key only while someone holds or waits
+// for it, so memory stays bounded by the number of in-flight bookings rather
+// than the number of events ever booked.
+type memoryLocker struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	timeout time.Duration
+}
+
+type memoryEntry struct {
+	sem  chan struct{} // capacity 1: a send acquires, a receive releases
+	refs int           // holders + waiters
+}
+
+func NewMemoryLocker(timeout time.Duration) Locker {
+	return &memoryLocker{entries: make(map[string]*memoryEntry), timeout: timeout}
+}
+
+func (l *memoryLocker) Lock(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	e, ok := l.entries[key]
+	if !ok {
+		e = &memoryEntry{sem: make(chan struct{}, 1)}
+		l.entries[key] = e
+	}
+	e.refs++
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+	select {
+	case e.sem <- struct{}{}:
+		var once sync.Once
+		return func() {
+			once.Do(func() {
+				<-e.sem
+				l.release(key, e)
+			})
+		}, nil
+	case <-ctx.Done():
+		l.release(key, e)
+		return nil, waitErr(ctx)
+	}
+}
+
+// release drops a reference and forgets the key once nobody needs it.
+func (l *memoryLocker) release(key string, e *memoryEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e.refs--
+	if e.refs == 0 {
+		delete(l.entries, key)
+	}
+}
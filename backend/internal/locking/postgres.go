@@ -0,0 +1,85 @@
+package locking
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// postgresLocker uses session-level advisory locks. Each held lock pins one
+// pooled connection until it is released, because advisory locks belong to
+// the session that took them.
+type postgresLocker struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+func NewPostgresLocker(db *gorm.DB, timeout time.Duration) Locker {
+	return &postgresLocker{db: db, timeout: timeout}
+}
+
+func (l *postgresLocker) Lock(ctx context.Context, key string) (func(), error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("postgresLocker: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, waitErr(ctx)
+		}
+		return nil, fmt.Errorf("postgresLocker conn: %w", err)
+	}
+	id := advisoryKey(key)
+	// Ending ctx cancels the wait server-side.
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", id); err != nil {
+		// Make sure nothing stays locked on a connection going back to the pool;
+		// if that can't be confirmed, the connection doesn't go back.
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock_all()"); err != nil {
+			discard(conn)
+		} else {
+			conn.Close()
+		}
+		if ctx.Err() != nil {
+			return nil, waitErr(ctx)
+		}
+		return nil, fmt.Errorf("postgresLocker lock: %w", err)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", id); err != nil {
+				log.Printf("postgresLocker: unlock %s: %v", key, err)
+				discard(conn)
+				return
+			}
+			conn.Close()
+		})
+	}, nil
+}
+
+// discard closes conn's session instead of returning it to the pool, which
+// drops any advisory lock it may still hold.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
+
+// advisoryKey maps a key onto the bigint space of pg_advisory_lock. The
+// "event:" namespace keeps it clear of other advisory-lock users.
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("event:" + key))
+	return int64(h.Sum64())
+}
@@ -37,9 +37,16 @@ func AuthRequired() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			return
 		}
-		claims := tok.Claims.(jwt.MapClaims)
-		c.Set(ContextKeyUserID, claims["sub"].(string))
-		c.Set(ContextKeyRole, claims["role"].(string))
+		// Ticket tokens carry typ "ticket" and no role; they never log anyone in.
+		claims, _ := tok.Claims.(jwt.MapClaims)
+		sub, okSub   := claims["sub"].(string)
+		role, okRole := claims["role"].(string)
+		if claims["typ"] == "ticket" || !okSub || !okRole || sub == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			return
+		}
+		c.Set(ContextKeyUserID, sub)
+		c.Set(ContextKeyRole, role)
 		c.Next()
 	}
 }
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/config"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplay is set on responses served from a stored key.
+	HeaderIdempotentReplay = "Idempotent-Replayed"
+)
+
+// Idempotency honours the Idempotency-Key header on mutating routes. The
+// first response per (user, key) is stored in the database and replayed for
+// retries within IDEMPOTENCY_TTL (default 24h); reusing a key with a
+// different method, path or body is rejected with 422. Must run after
+// AuthRequired. Requests without the header pass straight through.
+func Idempotency(repo repositories.IdempotencyRepository) gin.HandlerFunc {
+	ttl := config.Duration("IDEMPOTENCY_TTL", 24*time.Hour)
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "could not read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		uid := c.GetString(ContextKeyUserID)
+		hash := requestHash(c.Request.Method, c.Request.URL.Path, body)
+		rec := &models.IdempotencyKey{
+			UserID: uid, Key: key, RequestHash: hash, ExpiresAt: time.Now().Add(ttl),
+		}
+		ctx := c.Request.Context()
+		reserved, err := repo.Reserve(ctx, rec)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !reserved {
+			replay(c, repo, uid, key, hash)
+			return
+		}
+
+		// The outcome is recorded even if the client has gone away or the
+		// request deadline passed, so the key never stays stuck in flight.
+		store := context.WithoutCancel(ctx)
+		w := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			if p := recover(); p != nil {
+				_ = repo.Release(store, uid, key) // don't leave the key stuck in flight
+				panic(p)
+			}
+		}()
+		c.Next()
+
+		// Server errors are not stored, so the client can retry them.
+		if status := w.Status(); status >= http.StatusInternalServerError {
+			err = repo.Release(store, uid, key)
+		} else {
+			err = repo.Complete(store, uid, key, status, w.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("idempotency: storing key %q for user %s: %v", key, uid, err)
+		}
+	}
+}
+
+// replay answers a request whose key is already taken.
+func replay(c *gin.Context, repo repositories.IdempotencyRepository, uid, key, hash string) {
+	prev, err := repo.Find(c.Request.Context(), uid, key)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	switch {
+	case prev.RequestHash != hash:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+	case prev.StatusCode == 0:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still in progress"})
+	default:
+		c.Header(HeaderIdempotentReplay, "true")
+		c.Data(prev.StatusCode, "application/json; charset=utf-8", prev.ResponseBody)
+		c.Abort()
+	}
+}
+
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// capturingWriter tees the response body so it can be stored for replay.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,5 +1,8 @@
 package models
 
+import "time"
+
+
 // ── Auth DTOs ─────────────────────────────────────────
 
 type RegisterRequest struct {
@@ -22,20 +25,423 @@ type AuthResponse struct {
 // ── Event DTOs ────────────────────────────────────────
 
 type CreateEventRequest struct {
-	Title       string `json:"title" binding:"required,min=3,max=200"`
+	// TemplateID names one of the organizer's templates; its title,
+	// description, capacity and tiers fill in the ones left out here.
+	TemplateID  string `json:"template_id"`
+	// Title is required unless the template gives one.
+	Title       string `json:"title" binding:"omitempty,min=3,max=200"`
 	Description string `json:"description"`
-	Capacity    int    `json:"capacity" binding:"required,min=1"`
-	EventDate   string `json:"event_date" binding:"required"` // RFC3339
+	// Capacity defaults to the venue's default capacity.
+	Capacity    int    `json:"capacity" binding:"omitempty,min=1"`
+	// EventDate (the start) and EndsAt are RFC3339, or local times such as
+	// "2026-12-31T18:00" read in Timezone. EndsAt defaults to
+	// DefaultEventDuration after the start.
+	EventDate   string `json:"event_date" binding:"required"`
+	EndsAt      string `json:"ends_at"`
+	// Timezone is an IANA name; it defaults to the venue's, else UTC.
+	Timezone    string `json:"timezone"`
+	// Optional booking window (RFC3339). Sales close at event_date by default.
+	SalesOpenAt  string `json:"sales_open_at"`
+	SalesCloseAt string `json:"sales_close_at"`
+	Tiers       []TierRequest `json:"tiers" binding:"omitempty,dive"`
+	// Status is "published" (the default) or "draft".
+	Status string `json:"status" binding:"omitempty,oneof=draft published"`
+	// Categories are slugs of existing categories; Tags are free-form and
+	// stored lower-case.
+	Categories []string `json:"categories" binding:"omitempty,max=5,dive,required"`
+	Tags       []string `json:"tags" binding:"omitempty,max=10,dive,min=2,max=30"`
+	// VenueID places the event; Online marks it as streamed, with the join
+	// link in OnlineURL. An event can be both.
+	VenueID   string `json:"venue_id"`
+	Online    bool   `json:"online"`
+	OnlineURL string `json:"online_url" binding:"omitempty,url,max=500"`
+}
+
+// CloneEventRequest is the body of POST /api/events/:id/clone. The copy keeps
+// the source's details, tiers and labels but none of its registrations; its
+// end, sales window and tier sales windows move by as much as its start.
+type CloneEventRequest struct {
+	// EventDate is the copy's start, read like CreateEventRequest.EventDate
+	// in the source's timezone.
+	EventDate string `json:"event_date" binding:"required"`
+	// Title defaults to the source's.
+	Title  string `json:"title" binding:"omitempty,min=3,max=200"`
+	Status string `json:"status" binding:"omitempty,oneof=draft published"`
+}
+
+// Page size limits for GET /api/events.
+const (
+	DefaultEventPageSize = 20
+	MaxEventPageSize     = 100
+)
+
+// EventListQuery holds the query string of GET /api/events. Zero values mean
+// no filter; From and To bound event_date (RFC3339, inclusive).
+type EventListQuery struct {
+	From        time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To          time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	OrganizerID string    `form:"organizer_id"`
+	// Available keeps only events with seats left.
+	Available bool `form:"available"`
+	// Upcoming keeps only events that haven't started yet.
+	Upcoming bool `form:"upcoming"`
+	// Category (a slug) and Tag keep only events labelled with them.
+	Category string `form:"category"`
+	Tag      string `form:"tag"`
+	// Online, when given, keeps only online (true) or in-person (false)
+	// events.
+	Online *bool `form:"online"`
+	// Near ("lat,lng") keeps events at venues within Radius km of it,
+	// DefaultRadiusKm by default. The service parses it into Origin.
+	Near   string    `form:"near"`
+	Radius float64   `form:"radius" binding:"omitempty,gt=0,max=500"`
+	Origin *GeoPoint `form:"-"`
+	// Sort is date (the default), title or created; a leading "-" reverses
+	// it. distance sorts nearest first and needs Near.
+	Sort   string `form:"sort" binding:"omitempty,oneof=date -date title -title created -created distance"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// DefaultRadiusKm is the radius of a near= filter without radius=.
+const DefaultRadiusKm = 25
+
+// EventPage is one page of GET /api/events. Total counts every event that
+// matches the filters; Next is the URL of the following page, if any.
+type EventPage struct {
+	Events []EventResponse `json:"events"`
+	Count  int             `json:"count"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+	Next   string          `json:"next,omitempty"`
+}
+
+// EventSearchQuery holds the query string of GET /api/events/search.
+type EventSearchQuery struct {
+	Q      string `form:"q" binding:"required,min=2,max=200"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// EventMatch is one full-text search hit as the repository finds it. Title
+// and Snippet are HTML-escaped with the matched terms wrapped in <mark>.
+type EventMatch struct {
+	Event   Event
+	Rank    float64
+	Title   string
+	Snippet string
+}
+
+// EventSearchResult is an event in the search results, with its relevance
+// (higher is better) and highlighted text.
+type EventSearchResult struct {
+	*EventResponse
+	Rank      float64        `json:"rank"`
+	Highlight EventHighlight `json:"highlight"`
+}
+
+// EventHighlight is HTML: matched terms are wrapped in <mark> and the rest of
+// the text is escaped. Description is a snippet around the matches.
+type EventHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// EventSearchPage is one page of GET /api/events/search, best match first.
+type EventSearchPage struct {
+	Query   string              `json:"query"`
+	Results []EventSearchResult `json:"results"`
+	Count   int                 `json:"count"`
+	Total   int64               `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+	Next    string              `json:"next,omitempty"`
+}
+
+// CancelEventRequest is the body of POST /api/events/:id/cancel.
+type CancelEventRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// UpdateEventRequest is the body of PATCH /api/events/:id. Omitted fields are
+// left as they are; an empty sales_open_at or sales_close_at clears it.
+type UpdateEventRequest struct {
+	Title        *string `json:"title" binding:"omitempty,min=3,max=200"`
+	Description  *string `json:"description"`
+	Capacity     *int    `json:"capacity" binding:"omitempty,min=1"`
+	EventDate    *string `json:"event_date"` // as in CreateEventRequest
+	EndsAt       *string `json:"ends_at"`
+	// Timezone changes how local times in this request are read; stored
+	// times keep their instant.
+	Timezone     *string `json:"timezone"`
+	SalesOpenAt  *string `json:"sales_open_at"`
+	SalesCloseAt *string `json:"sales_close_at"`
+}
+
+// TierRequest defines one ticket tier of a new event. Omitting the sales
+// window keeps the tier on sale for as long as the event is.
+type TierRequest struct {
+	Name         string `json:"name" binding:"required,min=2,max=50"`
+	Description  string `json:"description"`
+	Capacity     int    `json:"capacity" binding:"required,min=1"`
+	SalesStartAt string `json:"sales_start_at"` // RFC3339, optional
+	SalesEndAt   string `json:"sales_end_at"`   // RFC3339, optional
 }
 
 type EventResponse struct {
 	*Event
+	// AvailableSeats excludes confirmed seats and active holds alike, since
+	// a hold claims its seats in Event.Registered until it is released.
 	AvailableSeats int `json:"available_seats"`
+	// SalesOpen reports whether bookings are accepted right now.
+	SalesOpen bool `json:"sales_open"`
+	// Phase is upcoming, ongoing or past, as of the response.
+	Phase EventPhase `json:"phase"`
+	// LocalStart and LocalEnd are the start and end in the event's timezone.
+	LocalStart string `json:"local_start"`
+	LocalEnd   string `json:"local_end"`
+	Cover      *MediaResponse  `json:"cover,omitempty"`
+	Gallery    []MediaResponse `json:"gallery,omitempty"`
+	// DistanceKm is the distance to the venue from the near= point of a
+	// list request.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+}
+
+// ── Collaborator DTOs ─────────────────────────────────
+
+// InviteCollaboratorRequest is the body of POST /api/events/:id/collaborators.
+// The invitee must already have an account; inviting someone who is already
+// a collaborator changes their role.
+type InviteCollaboratorRequest struct {
+	Email string           `json:"email" binding:"required,email"`
+	Role  CollaboratorRole `json:"role" binding:"required,oneof=co_organizer staff viewer"`
+}
+
+// ── Template DTOs ─────────────────────────────────────
+
+// CreateTemplateRequest is the body of POST /api/templates. With EventID the
+// template starts as a copy of that event's title, description, capacity and
+// tiers, and the other fields override it; without, Title is required.
+type CreateTemplateRequest struct {
+	Name        string                `json:"name" binding:"required,min=2,max=100"`
+	EventID     string                `json:"event_id"`
+	Title       string                `json:"title" binding:"omitempty,min=3,max=200"`
+	Description string                `json:"description"`
+	Capacity    int                   `json:"capacity" binding:"omitempty,min=1"`
+	Tiers       []TemplateTierRequest `json:"tiers" binding:"omitempty,dive"`
+}
+
+type TemplateTierRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=50"`
+	Description string `json:"description"`
+	Capacity    int    `json:"capacity" binding:"required,min=1"`
+}
+
+// ── Media DTOs ────────────────────────────────────────
+
+// Image variant sizes: the longest side of the medium and thumb copies.
+const (
+	MediumImageSide = 1280
+	ThumbImageSide  = 320
+)
+
+// MediaResponse is an event image with the URLs of its variants.
+type MediaResponse struct {
+	*EventMedia
+	URL          string `json:"url"`
+	MediumURL    string `json:"medium_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// ── Venue DTOs ────────────────────────────────────────
+
+// CreateVenueRequest is the body of POST /api/venues. Timezone is an IANA
+// name such as "America/New_York".
+type CreateVenueRequest struct {
+	Name            string   `json:"name" binding:"required,min=2,max=100"`
+	Address         string   `json:"address" binding:"required,max=300"`
+	City            string   `json:"city" binding:"max=100"`
+	Country         string   `json:"country" binding:"max=100"`
+	Latitude        *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude       *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Timezone        string   `json:"timezone" binding:"required"`
+	DefaultCapacity int      `json:"default_capacity" binding:"omitempty,min=1"`
+}
+
+// ── Category DTOs ─────────────────────────────────────
+
+// CreateCategoryRequest is the body of POST /api/categories. Slug defaults to
+// one derived from Name.
+type CreateCategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=50"`
+	Slug        string `json:"slug" binding:"omitempty,min=2,max=60"`
+	Description string `json:"description"`
+}
+
+// CategoryCount is a category with the number of listed events in it.
+type CategoryCount struct {
+	Category
+	EventCount int64 `json:"event_count"`
+}
+
+// TagCount is a tag with the number of listed events carrying it.
+type TagCount struct {
+	Name       string `json:"name"`
+	EventCount int64  `json:"event_count"`
+}
+
+// Taxonomy is the body of GET /api/categories.
+type Taxonomy struct {
+	Categories  []CategoryCount `json:"categories"`
+	PopularTags []TagCount      `json:"popular_tags"`
 }
 
 // ── Booking DTOs ──────────────────────────────────────
 
+// MaxSeatsPerBooking caps the quantity of a single group booking.
+const MaxSeatsPerBooking = 10
+
+// BookingRequest is the optional body of POST /api/events/:id/register.
+type BookingRequest struct {
+	// Quantity is the number of seats to claim, all or nothing. Defaults to
+	// len(Attendees), or 1 when no attendees are named.
+	Quantity int `json:"quantity" binding:"omitempty,min=1,max=10"`
+	// Attendees names the holder of each seat; required when Quantity > 1.
+	Attendees []AttendeeRequest `json:"attendees" binding:"omitempty,max=10,dive"`
+	// TierID picks the ticket tier; required when the event has tiers.
+	TierID string `json:"tier_id"`
+	// JoinWaitlist opts in to the waitlist when the event is full.
+	JoinWaitlist bool `json:"join_waitlist"`
+}
+
+type AttendeeRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
+}
+
 type BookingResponse struct {
 	Message      string        `json:"message"`
 	Registration *Registration `json:"registration,omitempty"`
 }
+
+type TransferRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ── Ticket DTOs ───────────────────────────────────────
+
+type TicketResponse struct {
+	RegistrationID string `json:"registration_id"`
+	EventID        string `json:"event_id"`
+	Token          string `json:"token"`
+	QRCodeURL      string `json:"qr_code_url"`
+	// OnlineURL is the join link of an online event.
+	OnlineURL string `json:"online_url,omitempty"`
+}
+
+type CheckInRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ── Stats DTOs ────────────────────────────────────────
+
+// StatsQuery is the query string of the stats endpoints. Bucket is the width
+// of the timeline's buckets, day by default. From and To bound the timeline
+// only; the totals always cover each event's whole history.
+type StatsQuery struct {
+	Bucket string    `form:"bucket" binding:"omitempty,oneof=day hour"`
+	From   time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// OrganizerID selects the events a user organizes or collaborates on,
+	// EventID a single event; the service sets them.
+	OrganizerID string `form:"-"`
+	EventID     string `form:"-"`
+}
+
+// StatsBucket is the booking activity in the bucket starting at Start (UTC).
+// Bookings counts registrations confirmed in it (new bookings, confirmed
+// holds and waitlist promotions) and Seats their seats; Cancellations counts
+// confirmed bookings cancelled in it.
+type StatsBucket struct {
+	Start         time.Time `json:"start"`
+	Bookings      int64     `json:"bookings"`
+	Seats         int64     `json:"seats"`
+	Cancellations int64     `json:"cancellations"`
+}
+
+// EventStats are one event's sales figures. Seat counts are of the
+// registrations' current status: SeatsSold counts ticketed seats (confirmed,
+// checked in or no-show), SeatsHeld pending holds. SellThroughPct is sold
+// seats as a percentage of capacity, CheckInRatePct checked-in seats as a
+// percentage of sold ones.
+//
+// LastSaleAt is when the latest sold seat was confirmed. For a sold-out event
+// that is SoldOutAt, and TimeToSellOutSeconds is how long after SalesStart
+// (sales_open_at, or the event's creation) it came.
+type EventStats struct {
+	EventID              string        `json:"event_id"`
+	Title                string        `json:"title"`
+	Status               EventStatus   `json:"status"`
+	EventDate            time.Time     `json:"event_date"`
+	SalesStart           time.Time     `json:"sales_start"`
+	Capacity             int64         `json:"capacity"`
+	SeatsSold            int64         `json:"seats_sold"`
+	SeatsHeld            int64         `json:"seats_held"`
+	Waitlisted           int64         `json:"waitlisted"`
+	SellThroughPct       float64       `json:"sell_through_pct"`
+	Bookings             int64         `json:"bookings"`
+	Cancellations        int64         `json:"cancellations"`
+	CancelledSeats       int64         `json:"cancelled_seats"`
+	CheckedIn            int64         `json:"checked_in"`
+	NoShows              int64         `json:"no_shows"`
+	CheckInRatePct       float64       `json:"check_in_rate_pct"`
+	LastSaleAt           *time.Time    `json:"last_sale_at,omitempty"`
+	SoldOutAt            *time.Time    `json:"sold_out_at,omitempty"`
+	TimeToSellOutSeconds *int64        `json:"time_to_sell_out_seconds,omitempty"`
+	Bucket               string        `json:"bucket,omitempty"`
+	Timeline             []StatsBucket `json:"timeline,omitempty"`
+}
+
+// StatsTotals adds up EventStats across an organizer's events.
+type StatsTotals struct {
+	Events         int     `json:"events"`
+	Capacity       int64   `json:"capacity"`
+	SeatsSold      int64   `json:"seats_sold"`
+	SellThroughPct float64 `json:"sell_through_pct"`
+	Bookings       int64   `json:"bookings"`
+	Cancellations  int64   `json:"cancellations"`
+	CheckedIn      int64   `json:"checked_in"`
+	CheckInRatePct float64 `json:"check_in_rate_pct"`
+	SoldOut        int     `json:"sold_out"`
+}
+
+// OrganizerStats is GET /api/organizer/stats: the figures of every event the
+// user organizes or collaborates on, their totals, and one timeline across
+// all of them.
+type OrganizerStats struct {
+	Totals   StatsTotals   `json:"totals"`
+	Events   []EventStats  `json:"events"`
+	Bucket   string        `json:"bucket"`
+	Timeline []StatsBucket `json:"timeline"`
+}
+
+// ── Maintenance DTOs ──────────────────────────────────
+
+// SeatDrift is one seat counter that disagrees with the registrations table.
+// TierID is nil for the event-wide counter (Event.Registered).
+type SeatDrift struct {
+	EventID  string  `json:"event_id"`
+	Title    string  `json:"title"`
+	TierID   *string `json:"tier_id,omitempty"`
+	TierName string  `json:"tier_name,omitempty"`
+	Stored   int     `json:"stored"`
+	Actual   int     `json:"actual"`
+}
+
+// ReconcileReport is the result of one seat-counter reconciliation pass.
+// Counters were only corrected when DryRun is false.
+type ReconcileReport struct {
+	DryRun        bool        `json:"dry_run"`
+	EventsChecked int         `json:"events_checked"`
+	Drift         []SeatDrift `json:"drift"`
+}
@@ -2,6 +2,8 @@
 package models
 
 import (
+	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +31,32 @@ func (u *User) BeforeCreate(_ *gorm.DB) error {
 	return nil
 }
 
+// EventStatus is an event's publication state.
+type EventStatus string
+
+const (
+	EventDraft     EventStatus = "draft"     // hidden from the list, closed to bookings
+	EventPublished EventStatus = "published"
+	EventCancelled EventStatus = "cancelled"
+)
+
+// eventTransitions lists the statuses each event status may move to.
+// cancelled is final.
+var eventTransitions = map[EventStatus][]EventStatus{
+	EventDraft:     {EventPublished, EventCancelled},
+	EventPublished: {EventCancelled},
+}
+
+// CanTransitionTo reports whether an event may move from s to to.
+func (s EventStatus) CanTransitionTo(to EventStatus) bool {
+	for _, next := range eventTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Event represents a ticketed event created by an organizer.
 type Event struct {
 	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
@@ -36,12 +64,40 @@ type Event struct {
 	Description string    `gorm:"type:text" json:"description"`
 	Capacity    int       `gorm:"not null;check:capacity > 0" json:"capacity"`
 	Registered  int       `gorm:"default:0" json:"registered"`
-	EventDate   time.Time `gorm:"not null" json:"event_date"`
-	OrganizerID string    `gorm:"type:varchar(36);not null" json:"organizer_id"`
+	// EventDate is the start. EndsAt is nil on events created before end
+	// times existed; see End.
+	EventDate   time.Time `gorm:"not null;index" json:"event_date"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	// Timezone is the IANA zone the event is held in, for local times.
+	Timezone    string    `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+	// Optional booking window. Sales close at the event start when
+	// SalesCloseAt is unset; see OnSaleAt.
+	SalesOpenAt  *time.Time `json:"sales_open_at,omitempty"`
+	SalesCloseAt *time.Time `json:"sales_close_at,omitempty"`
+	OrganizerID string    `gorm:"type:varchar(36);not null;index" json:"organizer_id"`
+	// VenueID is nil for online-only events and events whose location isn't
+	// announced yet. An online event may also have a venue (hybrid).
+	VenueID *string `gorm:"type:varchar(36);index" json:"venue_id,omitempty"`
+	Online  bool    `gorm:"not null;default:false" json:"online"`
+	// OnlineURL is the join link. It is only shown on tickets.
+	OnlineURL string `gorm:"type:varchar(500)" json:"-"`
+	Status      EventStatus `gorm:"type:varchar(20);not null;default:'published';index" json:"status"`
+	// Set when the event is cancelled.
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason string     `gorm:"type:varchar(500)" json:"cancel_reason,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// DeletedAt soft-deletes the event, keeping past registrations intact.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	Organizer     User           `gorm:"foreignKey:OrganizerID" json:"organizer,omitempty"`
+	Venue         *Venue         `gorm:"foreignKey:VenueID" json:"venue,omitempty"`
+	Tiers         []TicketTier   `gorm:"foreignKey:EventID" json:"tiers,omitempty"`
+	Media         []EventMedia   `gorm:"foreignKey:EventID" json:"-"`
+	// Collaborators are the users the organizer shares the event with.
+	Collaborators []EventCollaborator `gorm:"foreignKey:EventID" json:"-"`
+	Categories    []Category     `gorm:"many2many:event_categories" json:"categories"`
+	Tags          []Tag          `gorm:"many2many:event_tags" json:"tags"`
 	Registrations []Registration `gorm:"foreignKey:EventID" json:"-"`
 }
 
@@ -54,25 +110,372 @@ func (e *Event) BeforeCreate(_ *gorm.DB) error {
 
 func (e *Event) AvailableSeats() int { return e.Capacity - e.Registered }
 
+// DefaultEventDuration is the length assumed for events without an end time.
+const DefaultEventDuration = 2 * time.Hour
+
+// End returns EndsAt, or DefaultEventDuration after the start when unset.
+func (e *Event) End() time.Time {
+	if e.EndsAt != nil {
+		return *e.EndsAt
+	}
+	return e.EventDate.Add(DefaultEventDuration)
+}
+
+// EventPhase says where now falls relative to an event's start and end.
+type EventPhase string
+
+const (
+	PhaseUpcoming EventPhase = "upcoming"
+	PhaseOngoing  EventPhase = "ongoing"
+	PhasePast     EventPhase = "past"
+)
+
+// PhaseAt reports the event's phase at now. The end is exclusive.
+func (e *Event) PhaseAt(now time.Time) EventPhase {
+	switch {
+	case now.Before(e.EventDate):
+		return PhaseUpcoming
+	case now.Before(e.End()):
+		return PhaseOngoing
+	default:
+		return PhasePast
+	}
+}
+
+// OnSaleAt reports whether the event's booking window includes now.
+func (e *Event) OnSaleAt(now time.Time) bool {
+	if e.SalesOpenAt != nil && now.Before(*e.SalesOpenAt) {
+		return false
+	}
+	closeAt := e.EventDate
+	if e.SalesCloseAt != nil {
+		closeAt = *e.SalesCloseAt
+	}
+	return now.Before(closeAt)
+}
+
+// Venue is a physical place events are held at. Timezone is an IANA name
+// (Europe/Berlin); DefaultCapacity, when set, is the capacity of new events
+// that don't give one.
+type Venue struct {
+	ID              string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Name            string    `gorm:"type:varchar(100);not null" json:"name"`
+	Address         string    `gorm:"type:varchar(300);not null" json:"address"`
+	City            string    `gorm:"type:varchar(100)" json:"city,omitempty"`
+	Country         string    `gorm:"type:varchar(100)" json:"country,omitempty"`
+	Latitude        float64   `gorm:"not null;index:idx_venue_coords" json:"latitude"`
+	Longitude       float64   `gorm:"not null;index:idx_venue_coords" json:"longitude"`
+	Timezone        string    `gorm:"type:varchar(64);not null" json:"timezone"`
+	DefaultCapacity int       `gorm:"default:0" json:"default_capacity,omitempty"`
+	CreatedBy       string    `gorm:"type:varchar(36);not null" json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (v *Venue) BeforeCreate(_ *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Point returns the venue's coordinates.
+func (v *Venue) Point() GeoPoint { return GeoPoint{Lat: v.Latitude, Lng: v.Longitude} }
+
+// KmPerDegree is the length of one degree of latitude (and of longitude at
+// the equator) on a sphere of the Earth's mean radius.
+const KmPerDegree = earthRadiusKm * math.Pi / 180
+
+const earthRadiusKm = 6371.0
+
+// GeoPoint is a latitude/longitude pair in degrees.
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// DistanceKm is the great-circle (haversine) distance from p to q.
+func (p GeoPoint) DistanceKm(q GeoPoint) float64 {
+	rad := math.Pi / 180
+	dLat := (q.Lat - p.Lat) * rad
+	dLng := (q.Lng - p.Lng) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(p.Lat*rad)*math.Cos(q.Lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// MediaKind says where an event image is shown. An event has at most one
+// cover and up to MaxGalleryImages gallery images.
+type MediaKind string
+
+const (
+	MediaCover   MediaKind = "cover"
+	MediaGallery MediaKind = "gallery"
+)
+
+const MaxGalleryImages = 20
+
+// EventMedia is an uploaded event image. The files live in a
+// storage.BlobStore under the keys; MediumKey and ThumbKey are empty when the
+// original is already small enough to serve in their place.
+type EventMedia struct {
+	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	EventID     string    `gorm:"type:varchar(36);not null;index" json:"event_id"`
+	Kind        MediaKind `gorm:"type:varchar(20);not null" json:"kind"`
+	ContentType string    `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Width       int       `gorm:"not null" json:"width"`
+	Height      int       `gorm:"not null" json:"height"`
+	OriginalKey string    `gorm:"type:varchar(300);not null" json:"-"`
+	MediumKey   string    `gorm:"type:varchar(300)" json:"-"`
+	ThumbKey    string    `gorm:"type:varchar(300)" json:"-"`
+	// VariantType is the content type of the medium and thumb files.
+	VariantType string    `gorm:"type:varchar(50)" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (m *EventMedia) BeforeCreate(_ *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Keys returns every blob key the media uses.
+func (m *EventMedia) Keys() []string {
+	keys := []string{m.OriginalKey}
+	for _, k := range []string{m.MediumKey, m.ThumbKey} {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Category is an organizer-managed classification (Music, Tech …). Events
+// pick categories from the existing list by slug.
+type Category struct {
+	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Slug        string    `gorm:"type:varchar(60);uniqueIndex;not null" json:"slug"`
+	Name        string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	CreatedBy   string    `gorm:"type:varchar(36);not null" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (c *Category) BeforeCreate(_ *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// Tag is a free-form, lower-case label. Tags are created the first time an
+// event uses them, so the name is the key.
+type Tag struct {
+	Name string `gorm:"type:varchar(30);primaryKey"`
+}
+
+// MarshalJSON renders a tag as its bare name.
+func (t Tag) MarshalJSON() ([]byte, error) { return json.Marshal(t.Name) }
+
+// TicketTier is a ticket type within an event (General, VIP, Student …) with
+// its own capacity and sales window. Event.Capacity still caps the total
+// across all tiers; Event.Registered counts seats from every tier.
+type TicketTier struct {
+	ID           string     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	EventID      string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_tier_event_name" json:"event_id"`
+	Name         string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_tier_event_name" json:"name"`
+	Description  string     `gorm:"type:text" json:"description"`
+	Capacity     int        `gorm:"not null;check:capacity > 0" json:"capacity"`
+	Registered   int        `gorm:"default:0" json:"registered"`
+	SalesStartAt *time.Time `json:"sales_start_at,omitempty"`
+	SalesEndAt   *time.Time `json:"sales_end_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Computed on read, never stored.
+	AvailableSeats int  `gorm:"-" json:"available_seats"`
+	OnSale         bool `gorm:"-" json:"on_sale"`
+}
+
+func (t *TicketTier) BeforeCreate(_ *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// OnSaleAt reports whether the tier's sales window includes now.
+func (t *TicketTier) OnSaleAt(now time.Time) bool {
+	if t.SalesStartAt != nil && now.Before(*t.SalesStartAt) {
+		return false
+	}
+	if t.SalesEndAt != nil && !now.Before(*t.SalesEndAt) {
+		return false
+	}
+	return true
+}
+
+// CollaboratorRole is what a collaborator may do with an event beyond what
+// everyone can: a co-organizer everything the organizer can except delete
+// it, staff check tickets in and see the attendee list, a viewer see the
+// event's stats.
+type CollaboratorRole string
+
+const (
+	RoleCoOrganizer CollaboratorRole = "co_organizer"
+	RoleStaff       CollaboratorRole = "staff"
+	RoleViewer      CollaboratorRole = "viewer"
+)
+
+// EventCollaborator gives UserID a role on EventID. The organizer
+// (Event.OrganizerID) is never a collaborator: they always have full access.
+type EventCollaborator struct {
+	ID        string           `gorm:"type:varchar(36);primaryKey" json:"id"`
+	EventID   string           `gorm:"type:varchar(36);not null;uniqueIndex:idx_collaborator_event_user" json:"event_id"`
+	UserID    string           `gorm:"type:varchar(36);not null;uniqueIndex:idx_collaborator_event_user;index" json:"user_id"`
+	Role      CollaboratorRole `gorm:"type:varchar(20);not null" json:"role"`
+	InvitedBy string           `gorm:"type:varchar(36);not null" json:"invited_by"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+
+	User  *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Event *Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
+}
+
+func (c *EventCollaborator) BeforeCreate(_ *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// EventTemplate is an organizer's saved starting point for new events:
+// POST /api/events with its template_id takes the fields the request leaves
+// empty from it. Templates are private to their organizer; Name tells them
+// apart. There are no registration questions to carry: events don't have
+// any yet.
+type EventTemplate struct {
+	ID          string         `gorm:"type:varchar(36);primaryKey" json:"id"`
+	OrganizerID string         `gorm:"type:varchar(36);not null;uniqueIndex:idx_template_owner_name" json:"organizer_id"`
+	Name        string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_template_owner_name" json:"name"`
+	Title       string         `gorm:"type:varchar(200);not null" json:"title"`
+	Description string         `gorm:"type:text" json:"description"`
+	Capacity    int            `gorm:"default:0" json:"capacity,omitempty"`
+	Tiers       []TemplateTier `gorm:"foreignKey:TemplateID" json:"tiers"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (t *EventTemplate) BeforeCreate(_ *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// TemplateTier is a ticket tier of an EventTemplate. It has no sales window:
+// those are dates, which belong to the event.
+type TemplateTier struct {
+	ID          string `gorm:"type:varchar(36);primaryKey" json:"id"`
+	TemplateID  string `gorm:"type:varchar(36);not null;index" json:"template_id"`
+	Position    int    `gorm:"not null" json:"position"`
+	Name        string `gorm:"type:varchar(50);not null" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	Capacity    int    `gorm:"not null;check:capacity > 0" json:"capacity"`
+}
+
+func (t *TemplateTier) BeforeCreate(_ *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
 // RegistrationStatus enumerates booking states.
 type RegistrationStatus string
 
 const (
-	StatusConfirmed RegistrationStatus = "confirmed"
-	StatusCancelled RegistrationStatus = "cancelled"
+	StatusPending    RegistrationStatus = "pending" // timed seat hold awaiting confirmation
+	StatusConfirmed  RegistrationStatus = "confirmed"
+	StatusWaitlisted RegistrationStatus = "waitlisted"
+	StatusCheckedIn  RegistrationStatus = "checked_in"
+	StatusNoShow     RegistrationStatus = "no_show" // confirmed but never checked in
+	StatusCancelled  RegistrationStatus = "cancelled"
+	StatusRefunded   RegistrationStatus = "refunded" // cancelled or no-show, money returned
 )
 
+// registrationTransitions is the lifecycle state machine: the statuses each
+// status may move to. Anything not listed is rejected by CanTransitionTo.
+// checked_in and refunded are final; a no-show may still check in late.
+var registrationTransitions = map[RegistrationStatus][]RegistrationStatus{
+	StatusPending:    {StatusConfirmed, StatusCancelled},
+	StatusWaitlisted: {StatusConfirmed, StatusCancelled},
+	StatusConfirmed:  {StatusCheckedIn, StatusNoShow, StatusCancelled},
+	StatusNoShow:     {StatusCheckedIn, StatusRefunded},
+	StatusCancelled:  {StatusRefunded},
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to to.
+func (s RegistrationStatus) CanTransitionTo(to RegistrationStatus) bool {
+	return to.in(registrationTransitions[s])
+}
+
+// ActiveStatuses are the states that count as a live booking. A user may hold
+// at most one registration in these states per event; the partial unique index
+// idx_user_event_active (see database.Migrate) enforces this.
+var ActiveStatuses = []RegistrationStatus{StatusPending, StatusConfirmed, StatusWaitlisted, StatusCheckedIn, StatusNoShow}
+
+// SeatStatuses are the states that occupy seats in Event.Registered (and the
+// tier's counter). A no-show keeps its seat: it was sold, just not used.
+var SeatStatuses = []RegistrationStatus{StatusPending, StatusConfirmed, StatusCheckedIn, StatusNoShow}
+
+// TicketedStatuses are the states of a sold, paid-for booking: the attendee
+// list and ticket endpoints deal only in these.
+var TicketedStatuses = []RegistrationStatus{StatusConfirmed, StatusCheckedIn, StatusNoShow}
+
+// HoldsSeat reports whether a registration in this status occupies seats in
+// Event.Registered (and its tier's counter).
+func (s RegistrationStatus) HoldsSeat() bool { return s.in(SeatStatuses) }
+
+// Ticketed reports whether s is one of TicketedStatuses.
+func (s RegistrationStatus) Ticketed() bool { return s.in(TicketedStatuses) }
+
+func (s RegistrationStatus) in(set []RegistrationStatus) bool {
+	for _, v := range set {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // Registration links a User to an Event.
 type Registration struct {
-	ID        string             `gorm:"type:varchar(36);primaryKey" json:"id"`
-	UserID    string             `gorm:"type:varchar(36);not null;uniqueIndex:idx_user_event_status" json:"user_id"`
-	EventID   string             `gorm:"type:varchar(36);not null;uniqueIndex:idx_user_event_status" json:"event_id"`
-	Status    RegistrationStatus `gorm:"type:varchar(20);default:'confirmed';uniqueIndex:idx_user_event_status" json:"status"`
-	CreatedAt time.Time          `json:"created_at"`
-	UpdatedAt time.Time          `json:"updated_at"`
+	ID          string             `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID      string             `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	EventID     string             `gorm:"type:varchar(36);not null;index" json:"event_id"`
+	Status      RegistrationStatus `gorm:"type:varchar(20);default:'confirmed'" json:"status"`
+	Quantity    int                `gorm:"not null;default:1;check:quantity > 0" json:"quantity"`
+	TierID      *string            `gorm:"type:varchar(36);index" json:"tier_id,omitempty"`
+	// HoldExpiresAt is set on pending holds; the sweeper releases them after.
+	HoldExpiresAt *time.Time `gorm:"index" json:"hold_expires_at,omitempty"`
+	CancelledAt *time.Time         `json:"cancelled_at,omitempty"`
+	CheckedInAt *time.Time         `json:"checked_in_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+
+	// WaitlistPosition is the 1-based queue position of a waitlisted
+	// registration. It is computed on read, never stored.
+	WaitlistPosition int `gorm:"-" json:"waitlist_position,omitempty"`
 
-	User  User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Event Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	User      User                   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Event     Event                  `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Tier      *TicketTier            `gorm:"foreignKey:TierID" json:"tier,omitempty"`
+	Attendees []RegistrationAttendee `gorm:"foreignKey:RegistrationID" json:"attendees,omitempty"`
+	Transfers []RegistrationTransfer `gorm:"foreignKey:RegistrationID" json:"transfers,omitempty"`
+	History   []RegistrationStatusChange `gorm:"foreignKey:RegistrationID" json:"history,omitempty"`
 }
 
 func (r *Registration) BeforeCreate(_ *gorm.DB) error {
@@ -81,3 +484,70 @@ func (r *Registration) BeforeCreate(_ *gorm.DB) error {
 	}
 	return nil
 }
+
+// RegistrationAttendee names the person holding one seat of a group booking.
+type RegistrationAttendee struct {
+	ID             string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	RegistrationID string    `gorm:"type:varchar(36);not null;index" json:"registration_id"`
+	Seat           int       `gorm:"not null" json:"seat"` // 1-based within the booking
+	Name           string    `gorm:"type:varchar(100);not null" json:"name"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (a *RegistrationAttendee) BeforeCreate(_ *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RegistrationTransfer records one hand-over of a registration between users.
+type RegistrationTransfer struct {
+	ID             string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	RegistrationID string    `gorm:"type:varchar(36);not null;index" json:"registration_id"`
+	FromUserID     string    `gorm:"type:varchar(36);not null" json:"from_user_id"`
+	ToUserID       string    `gorm:"type:varchar(36);not null" json:"to_user_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (t *RegistrationTransfer) BeforeCreate(_ *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// RegistrationStatusChange records one lifecycle transition. FromStatus is
+// empty for the row written when the registration is created. ActorID is the
+// user who caused the change, or nil for the system (hold expiry, waitlist
+// promotion).
+type RegistrationStatusChange struct {
+	ID             string             `gorm:"type:varchar(36);primaryKey" json:"id"`
+	RegistrationID string             `gorm:"type:varchar(36);not null;index" json:"registration_id"`
+	FromStatus     RegistrationStatus `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus       RegistrationStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	ActorID        *string            `gorm:"type:varchar(36)" json:"actor_id,omitempty"`
+	Reason         string             `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+func (c *RegistrationStatusChange) BeforeCreate(_ *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// IdempotencyKey stores the first response to a request carrying an
+// Idempotency-Key header so retries can be replayed instead of re-executed.
+// Keys are scoped per user. StatusCode is 0 while the first request is still
+// in flight.
+type IdempotencyKey struct {
+	UserID       string    `gorm:"type:varchar(36);primaryKey"`
+	Key          string    `gorm:"column:idem_key;type:varchar(255);primaryKey"`
+	RequestHash  string    `gorm:"type:varchar(64);not null"` // sha256 of method, path and body
+	StatusCode   int       `gorm:"not null;default:0"`
+	ResponseBody []byte
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,67 @@
+// Package notify carries domain events from the services to whatever reacts
+// to them — e-mail, webhooks, a message queue. Services publish after their
+// transaction commits, so a Notifier only ever hears about changes that
+// happened; delivery is best effort and a Notifier must not block for long.
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Event is a domain event. Name identifies its type, e.g. "event.cancelled".
+type Event interface {
+	Name() string
+}
+
+// Notifier consumes domain events.
+type Notifier interface {
+	Notify(ctx context.Context, e Event)
+}
+
+// EventCancelled is published when an organizer cancels an event. It lists
+// every registration the cancellation cancelled, so attendees can be told.
+type EventCancelled struct {
+	EventID       string                   `json:"event_id"`
+	Title         string                   `json:"title"`
+	Reason        string                   `json:"reason"`
+	CancelledBy   string                   `json:"cancelled_by"`
+	CancelledAt   time.Time                `json:"cancelled_at"`
+	Registrations []CancelledRegistration `json:"registrations"`
+}
+
+type CancelledRegistration struct {
+	RegistrationID string `json:"registration_id"`
+	UserID         string `json:"user_id"`
+}
+
+func (EventCancelled) Name() string { return "event.cancelled" }
+
+// CollaboratorInvited is published when a user is given a role on an event,
+// or has their role changed, so they can be told.
+type CollaboratorInvited struct {
+	EventID   string `json:"event_id"`
+	Title     string `json:"title"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	InvitedBy string `json:"invited_by"`
+}
+
+func (CollaboratorInvited) Name() string { return "event.collaborator_invited" }
+
+// LogNotifier writes each domain event to the application log. It is the
+// default until a real channel is wired in.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(_ context.Context, e Event) {
+	switch e := e.(type) {
+	case EventCancelled:
+		log.Printf("DOMAIN EVENT %s | event=%s registrations=%d reason=%q", e.Name(), e.EventID, len(e.Registrations), e.Reason)
+	case CollaboratorInvited:
+		log.Printf("DOMAIN EVENT %s | event=%s user=%s role=%s", e.Name(), e.EventID, e.UserID, e.Role)
+	default:
+		log.Printf("DOMAIN EVENT %s", e.Name())
+	}
+}
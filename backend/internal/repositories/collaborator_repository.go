@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+// CollaboratorRepository stores event memberships. Access checks don't go
+// through it: EventRepository.FindByID loads an event's collaborators.
+type CollaboratorRepository interface {
+	Create(ctx context.Context, c *models.EventCollaborator) error
+	// UpdateRole changes a collaborator's role and who last granted it.
+	UpdateRole(ctx context.Context, c *models.EventCollaborator) error
+	// Find returns userID's membership of eventID, or gorm.ErrRecordNotFound.
+	Find(ctx context.Context, eventID, userID string) (*models.EventCollaborator, error)
+	// ListByEvent returns eventID's collaborators with their users, oldest
+	// first.
+	ListByEvent(ctx context.Context, eventID string) ([]models.EventCollaborator, error)
+	// ListByUser returns userID's memberships of events that still exist,
+	// with the events, soonest first.
+	ListByUser(ctx context.Context, userID string) ([]models.EventCollaborator, error)
+	// Delete removes userID from eventID, reporting whether they were on it.
+	Delete(ctx context.Context, eventID, userID string) (bool, error)
+}
+
+type collaboratorRepository struct{ db *gorm.DB }
+
+func NewCollaboratorRepository(db *gorm.DB) CollaboratorRepository {
+	return &collaboratorRepository{db: db}
+}
+
+func (r *collaboratorRepository) Create(ctx context.Context, c *models.EventCollaborator) error {
+	if err := r.db.WithContext(ctx).Create(c).Error; err != nil {
+		return fmt.Errorf("collaboratorRepo.Create: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepository) UpdateRole(ctx context.Context, c *models.EventCollaborator) error {
+	err := r.db.WithContext(ctx).Model(c).Select("role", "invited_by", "updated_at").Updates(c).Error
+	if err != nil {
+		return fmt.Errorf("collaboratorRepo.UpdateRole: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepository) Find(ctx context.Context, eventID, userID string) (*models.EventCollaborator, error) {
+	var c models.EventCollaborator
+	if err := r.db.WithContext(ctx).First(&c, "event_id = ? AND user_id = ?", eventID, userID).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *collaboratorRepository) ListByEvent(ctx context.Context, eventID string) ([]models.EventCollaborator, error) {
+	var cs []models.EventCollaborator
+	err := r.db.WithContext(ctx).Preload("User").
+		Where("event_id = ?", eventID).Order("created_at asc, id asc").Find(&cs).Error
+	if err != nil {
+		return nil, fmt.Errorf("collaboratorRepo.ListByEvent: %w", err)
+	}
+	return cs, nil
+}
+
+func (r *collaboratorRepository) ListByUser(ctx context.Context, userID string) ([]models.EventCollaborator, error) {
+	var cs []models.EventCollaborator
+	err := r.db.WithContext(ctx).Preload("Event").
+		Joins("JOIN events ON events.id = event_collaborators.event_id AND events.deleted_at IS NULL").
+		Where("event_collaborators.user_id = ?", userID).
+		Order("events.event_date asc, events.id asc").Find(&cs).Error
+	if err != nil {
+		return nil, fmt.Errorf("collaboratorRepo.ListByUser: %w", err)
+	}
+	return cs, nil
+}
+
+func (r *collaboratorRepository) Delete(ctx context.Context, eventID, userID string) (bool, error) {
+	res := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).
+		Delete(&models.EventCollaborator{})
+	if res.Error != nil {
+		return false, fmt.Errorf("collaboratorRepo.Delete: %w", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
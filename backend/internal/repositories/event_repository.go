@@ -1,65 +1,314 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"github.com/Amrutavarshini24/Eventregistration/internal/models"
 )
 
+// EventRepository methods that take tx run on that transaction, which
+// carries the caller's context (see db.WithContext(ctx).Transaction).
 type EventRepository interface {
-	Create(event *models.Event) error
-	FindByID(id string) (*models.Event, error)
-	List() ([]models.Event, error)
-	// IncrementRegistered claims one seat atomically inside tx.
-	// Returns (event, true) on success, (event, false) when full.
-	IncrementRegistered(tx *gorm.DB, eventID string) (*models.Event, bool, error)
+	Create(ctx context.Context, event *models.Event) error
+	FindByID(ctx context.Context, id string) (*models.Event, error)
+	// List returns one page of the non-draft events matching q, and how many
+	// match in total. q.Limit must be set.
+	List(ctx context.Context, q *models.EventListQuery) ([]models.Event, int64, error)
+	// Search returns one page of the non-draft events matching the words in
+	// query, most relevant first, and how many match in total.
+	Search(ctx context.Context, query string, limit, offset int) ([]models.EventMatch, int64, error)
+	// Update saves ev's editable fields inside tx. Returns false, changing
+	// nothing, when ev.Capacity is below the seats already registered.
+	Update(tx *gorm.DB, ev *models.Event) (bool, error)
+	// UpdateStatus moves eventID from one status to another inside tx,
+	// stamping cancelled_at and reason on cancellation. Returns false when the
+	// event is no longer in the expected status.
+	UpdateStatus(tx *gorm.DB, eventID string, from, to models.EventStatus, reason string) (bool, error)
+	// Delete soft-deletes the event inside tx.
+	Delete(tx *gorm.DB, eventID string) error
+	// IDs returns the ID of every event in any status. Deleted events are
+	// left out: they can only be deleted with no active registrations, so
+	// there are no seats left to count.
+	IDs(ctx context.Context) ([]string, error)
+	// IncrementRegistered claims n seats atomically inside tx, all or nothing.
+	// Returns (event, true) on success, (event, false) when they don't fit.
+	IncrementRegistered(tx *gorm.DB, eventID string, n int) (*models.Event, bool, error)
+	// DecrementRegistered releases n seats inside tx.
+	DecrementRegistered(tx *gorm.DB, eventID string, n int) error
+	// IncrementTierRegistered is IncrementRegistered for a single ticket tier.
+	IncrementTierRegistered(tx *gorm.DB, tierID string, n int) (bool, error)
+	// DecrementTierRegistered is DecrementRegistered for a single ticket tier.
+	DecrementTierRegistered(tx *gorm.DB, tierID string, n int) error
+	// SetRegistered overwrites the event's seat counter inside tx. Only the
+	// seat reconciler uses it; bookings go through Increment/Decrement.
+	SetRegistered(tx *gorm.DB, eventID string, n int) error
+	// SetTierRegistered is SetRegistered for a single ticket tier.
+	SetTierRegistered(tx *gorm.DB, tierID string, n int) error
 }
 
 type eventRepository struct{ db *gorm.DB }
 
 func NewEventRepository(db *gorm.DB) EventRepository { return &eventRepository{db: db} }
 
-func (r *eventRepository) Create(event *models.Event) error {
-	return r.db.Create(event).Error
+// eventDetails preloads what an EventResponse shows alongside the event, and
+// the collaborators that access checks (services.managedEvent) look at.
+func eventDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Organizer").Preload("Venue").Preload("Tiers").Preload("Categories").Preload("Tags").
+		Preload("Media", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc, id asc") }).
+		Preload("Collaborators")
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	return r.db.WithContext(ctx).Create(event).Error
 }
 
-func (r *eventRepository) FindByID(id string) (*models.Event, error) {
+func (r *eventRepository) FindByID(ctx context.Context, id string) (*models.Event, error) {
 	var e models.Event
-	if err := r.db.Preload("Organizer").First(&e, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(eventDetails).First(&e, "id = ?", id).Error; err != nil {
 		return nil, fmt.Errorf("eventRepo.FindByID: %w", err)
 	}
 	return &e, nil
 }
 
-func (r *eventRepository) List() ([]models.Event, error) {
+// eventOrders maps EventListQuery.Sort to ORDER BY clauses. id breaks ties so
+// pages don't overlap or skip rows when many events share a date or title.
+var eventOrders = map[string]string{
+	"":         "event_date asc, id asc",
+	"date":     "event_date asc, id asc",
+	"-date":    "event_date desc, id desc",
+	"title":    "title asc, id asc",
+	"-title":   "title desc, id desc",
+	"created":  "created_at asc, id asc",
+	"-created": "created_at desc, id desc",
+}
+
+func (r *eventRepository) List(ctx context.Context, q *models.EventListQuery) ([]models.Event, int64, error) {
+	var order interface{}
+	if q.Sort == "distance" {
+		if q.Origin == nil {
+			return nil, 0, fmt.Errorf("eventRepo.List: sort by distance without an origin")
+		}
+		dist, args := venueDistanceSQL(*q.Origin)
+		order = clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(SELECT " + dist + " FROM venues WHERE venues.id = events.venue_id) ASC, id ASC",
+			Vars:               args,
+			WithoutParentheses: true,
+		}}
+	} else if o, ok := eventOrders[q.Sort]; ok {
+		order = o
+	} else {
+		return nil, 0, fmt.Errorf("eventRepo.List: unknown sort %q", q.Sort)
+	}
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("status <> ?", models.EventDraft)
+		if !q.From.IsZero() {
+			db = db.Where("event_date >= ?", q.From)
+		}
+		if !q.To.IsZero() {
+			db = db.Where("event_date <= ?", q.To)
+		}
+		if q.OrganizerID != "" {
+			db = db.Where("organizer_id = ?", q.OrganizerID)
+		}
+		if q.Available {
+			db = db.Where("registered < capacity")
+		}
+		if q.Upcoming {
+			db = db.Where("event_date > ?", time.Now())
+		}
+		if q.Category != "" {
+			db = db.Where("id IN (?)", r.db.Table("event_categories ec").Select("ec.event_id").
+				Joins("JOIN categories c ON c.id = ec.category_id").Where("c.slug = ?", q.Category))
+		}
+		if q.Tag != "" {
+			db = db.Where("id IN (?)", r.db.Table("event_tags").Select("event_id").
+				Where("tag_name = ?", strings.ToLower(q.Tag)))
+		}
+		if q.Online != nil {
+			db = db.Where("online = ?", *q.Online)
+		}
+		if q.Origin != nil {
+			db = db.Where("venue_id IN (?)", nearVenues(r.db, *q.Origin, q.Radius))
+		}
+		return db
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Event{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("eventRepo.List count: %w", err)
+	}
 	var evs []models.Event
-	if err := r.db.Preload("Organizer").Order("event_date asc").Find(&evs).Error; err != nil {
-		return nil, fmt.Errorf("eventRepo.List: %w", err)
+	err := r.db.WithContext(ctx).Scopes(eventDetails, filter).
+		Order(order).Limit(q.Limit).Offset(q.Offset).Find(&evs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("eventRepo.List: %w", err)
 	}
-	return evs, nil
+	return evs, total, nil
+}
+
+// venueDistanceSQL is the squared distance in km² from o to a venues row,
+// treating the area around o as flat (equirectangular projection). It needs
+// only arithmetic, so it runs on SQLite as well as Postgres. At city and
+// regional scale it stays close to the great-circle distance; it doesn't
+// handle the antimeridian.
+func venueDistanceSQL(o models.GeoPoint) (string, []interface{}) {
+	kx := models.KmPerDegree * math.Cos(o.Lat*math.Pi/180)
+	ky := models.KmPerDegree
+	return "((venues.longitude - ?) * ?) * ((venues.longitude - ?) * ?) + " +
+			"((venues.latitude - ?) * ?) * ((venues.latitude - ?) * ?)",
+		[]interface{}{o.Lng, kx, o.Lng, kx, o.Lat, ky, o.Lat, ky}
+}
+
+// nearVenues selects the IDs of venues within radiusKm of o. The bounding box
+// lets the coordinate index narrow the scan before distances are computed.
+func nearVenues(db *gorm.DB, o models.GeoPoint, radiusKm float64) *gorm.DB {
+	dLat := radiusKm / models.KmPerDegree
+	q := db.Model(&models.Venue{}).Select("id").
+		Where("latitude BETWEEN ? AND ?", o.Lat-dLat, o.Lat+dLat)
+	if cos := math.Cos(o.Lat * math.Pi / 180); cos > 0.01 {
+		dLng := dLat / cos
+		q = q.Where("longitude BETWEEN ? AND ?", o.Lng-dLng, o.Lng+dLng)
+	}
+	dist, args := venueDistanceSQL(o)
+	return q.Where(dist+" <= ?", append(args, radiusKm*radiusKm)...)
+}
+
+// Update is conditional on registered <= the new capacity, so a shrink can
+// never strand seats that a booking claimed after the caller's read.
+func (r *eventRepository) Update(tx *gorm.DB, ev *models.Event) (bool, error) {
+	res := tx.Model(&models.Event{}).
+		Where("id = ? AND registered <= ?", ev.ID, ev.Capacity).
+		Select("title", "description", "capacity", "event_date", "ends_at", "timezone",
+			"sales_open_at", "sales_close_at", "updated_at").
+		Updates(ev)
+	if res.Error != nil {
+		return false, fmt.Errorf("eventRepo.Update: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *eventRepository) UpdateStatus(tx *gorm.DB, eventID string, from, to models.EventStatus, reason string) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	if to == models.EventCancelled {
+		updates["cancelled_at"] = time.Now()
+		updates["cancel_reason"] = reason
+	}
+	res := tx.Model(&models.Event{}).Where("id = ? AND status = ?", eventID, from).Updates(updates)
+	if res.Error != nil {
+		return false, fmt.Errorf("eventRepo.UpdateStatus: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *eventRepository) Delete(tx *gorm.DB, eventID string) error {
+	if err := tx.Delete(&models.Event{}, "id = ?", eventID).Error; err != nil {
+		return fmt.Errorf("eventRepo.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *eventRepository) IDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	if err := r.db.WithContext(ctx).Model(&models.Event{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("eventRepo.IDs: %w", err)
+	}
+	return ids, nil
 }
 
 // IncrementRegistered — Layer 3 of the concurrency defence.
 // Uses SELECT FOR UPDATE (Postgres) + conditional UPDATE to guarantee
 // no overbooking even across multiple server nodes.
-func (r *eventRepository) IncrementRegistered(tx *gorm.DB, eventID string) (*models.Event, bool, error) {
+func (r *eventRepository) IncrementRegistered(tx *gorm.DB, eventID string, n int) (*models.Event, bool, error) {
 	var e models.Event
 	// Lock the row for the transaction duration (Postgres).
 	if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&e, "id = ?", eventID).Error; err != nil {
 		return nil, false, fmt.Errorf("eventRepo.IncrementRegistered lock: %w", err)
 	}
-	if e.Registered >= e.Capacity {
+	if e.Registered+n > e.Capacity {
 		return &e, false, nil
 	}
 	res := tx.Model(&models.Event{}).
-		Where("id = ? AND registered < capacity", eventID).
-		UpdateColumn("registered", gorm.Expr("registered + ?", 1))
+		Where("id = ? AND registered + ? <= capacity", eventID, n).
+		UpdateColumn("registered", gorm.Expr("registered + ?", n))
 	if res.Error != nil {
 		return nil, false, fmt.Errorf("eventRepo.IncrementRegistered update: %w", res.Error)
 	}
 	if res.RowsAffected == 0 {
 		return &e, false, nil // race — another tx won
 	}
-	e.Registered++
+	e.Registered += n
 	return &e, true, nil
 }
+
+// DecrementRegistered is the release-side counterpart of IncrementRegistered.
+// The row lock keeps it ordered with concurrent claims; the CASE clamps at
+// zero so a drifted counter never goes negative.
+func (r *eventRepository) DecrementRegistered(tx *gorm.DB, eventID string, n int) error {
+	var e models.Event
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&e, "id = ?", eventID).Error; err != nil {
+		return fmt.Errorf("eventRepo.DecrementRegistered lock: %w", err)
+	}
+	res := tx.Model(&models.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumn("registered", gorm.Expr("CASE WHEN registered >= ? THEN registered - ? ELSE 0 END", n, n))
+	if res.Error != nil {
+		return fmt.Errorf("eventRepo.DecrementRegistered update: %w", res.Error)
+	}
+	return nil
+}
+
+// IncrementTierRegistered applies the same lock + conditional UPDATE as
+// IncrementRegistered to a tier's own counter. Callers claim the event row
+// first and the tier row second, so lock order is consistent everywhere.
+func (r *eventRepository) IncrementTierRegistered(tx *gorm.DB, tierID string, n int) (bool, error) {
+	var t models.TicketTier
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&t, "id = ?", tierID).Error; err != nil {
+		return false, fmt.Errorf("eventRepo.IncrementTierRegistered lock: %w", err)
+	}
+	if t.Registered+n > t.Capacity {
+		return false, nil
+	}
+	res := tx.Model(&models.TicketTier{}).
+		Where("id = ? AND registered + ? <= capacity", tierID, n).
+		UpdateColumn("registered", gorm.Expr("registered + ?", n))
+	if res.Error != nil {
+		return false, fmt.Errorf("eventRepo.IncrementTierRegistered update: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *eventRepository) DecrementTierRegistered(tx *gorm.DB, tierID string, n int) error {
+	var t models.TicketTier
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").First(&t, "id = ?", tierID).Error; err != nil {
+		return fmt.Errorf("eventRepo.DecrementTierRegistered lock: %w", err)
+	}
+	res := tx.Model(&models.TicketTier{}).
+		Where("id = ?", tierID).
+		UpdateColumn("registered", gorm.Expr("CASE WHEN registered >= ? THEN registered - ? ELSE 0 END", n, n))
+	if res.Error != nil {
+		return fmt.Errorf("eventRepo.DecrementTierRegistered update: %w", res.Error)
+	}
+	return nil
+}
+
+func (r *eventRepository) SetRegistered(tx *gorm.DB, eventID string, n int) error {
+	res := tx.Model(&models.Event{}).Where("id = ?", eventID).UpdateColumn("registered", n)
+	if res.Error != nil {
+		return fmt.Errorf("eventRepo.SetRegistered: %w", res.Error)
+	}
+	return nil
+}
+
+func (r *eventRepository) SetTierRegistered(tx *gorm.DB, tierID string, n int) error {
+	res := tx.Model(&models.TicketTier{}).Where("id = ?", tierID).UpdateColumn("registered", n)
+	if res.Error != nil {
+		return fmt.Errorf("eventRepo.SetTierRegistered: %w", res.Error)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+// Search finds events by title and description with the engine of the
+// database in use: the search_vector column on Postgres, the events_fts table
+// on SQLite (both created by database.Migrate). Each term in query is matched
+// as a prefix, and every term must match.
+//
+// Matches are ranked first, then the page of events is loaded with the same
+// preloads as FindByID.
+func (r *eventRepository) Search(ctx context.Context, query string, limit, offset int) ([]models.EventMatch, int64, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	var countSQL, pageSQL string
+	var countArgs, pageArgs []interface{}
+	switch name := r.db.Dialector.Name(); name {
+	case "postgres":
+		prefixes := make([]string, len(terms))
+		for i, t := range terms {
+			prefixes[i] = t + ":*"
+		}
+		tsq := strings.Join(prefixes, " & ")
+		opts := fmt.Sprintf(`StartSel="%s", StopSel="%s"`, markOpen, markClose)
+		countSQL = `SELECT count(*) FROM events e
+			WHERE e.search_vector @@ to_tsquery('english', ?) AND e.status <> ? AND e.deleted_at IS NULL`
+		countArgs = []interface{}{tsq, models.EventDraft}
+		pageSQL = `SELECT e.id AS event_id, ts_rank(e.search_vector, q) AS rank,
+				ts_headline('english', e.title, q, ?) AS title,
+				ts_headline('english', coalesce(e.description, ''), q, ?) AS snippet
+			FROM events e, to_tsquery('english', ?) q
+			WHERE e.search_vector @@ q AND e.status <> ? AND e.deleted_at IS NULL
+			ORDER BY rank DESC, e.event_date ASC, e.id ASC
+			LIMIT ? OFFSET ?`
+		pageArgs = []interface{}{opts + ", HighlightAll=true",
+			opts + `, MaxFragments=2, MaxWords=24, MinWords=8, FragmentDelimiter=" … "`,
+			tsq, models.EventDraft, limit, offset}
+	case "sqlite":
+		quoted := make([]string, len(terms))
+		for i, t := range terms {
+			quoted[i] = `"` + t + `"*`
+		}
+		match := strings.Join(quoted, " ")
+		countSQL = `SELECT count(*) FROM events_fts JOIN events e ON e.rowid = events_fts.rowid
+			WHERE events_fts MATCH ? AND e.status <> ? AND e.deleted_at IS NULL`
+		countArgs = []interface{}{match, models.EventDraft}
+		// bm25 is lower-is-better; negate it so rank reads the same on both
+		// databases. Title matches weigh ten times description matches.
+		pageSQL = `SELECT e.id AS event_id, -bm25(events_fts, 10.0, 1.0) AS rank,
+				highlight(events_fts, 0, ?, ?) AS title,
+				snippet(events_fts, 1, ?, ?, ' … ', 24) AS snippet
+			FROM events_fts JOIN events e ON e.rowid = events_fts.rowid
+			WHERE events_fts MATCH ? AND e.status <> ? AND e.deleted_at IS NULL
+			ORDER BY rank DESC, e.event_date ASC, e.id ASC
+			LIMIT ? OFFSET ?`
+		pageArgs = []interface{}{markOpen, markClose, markOpen, markClose,
+			match, models.EventDraft, limit, offset}
+	default:
+		return nil, 0, fmt.Errorf("eventRepo.Search: unsupported dialect %s", name)
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw(countSQL, countArgs...).Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("eventRepo.Search count: %w", err)
+	}
+	var rows []struct {
+		EventID string
+		Rank    float64
+		Title   string
+		Snippet string
+	}
+	if err := r.db.WithContext(ctx).Raw(pageSQL, pageArgs...).Scan(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("eventRepo.Search: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, total, nil
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.EventID
+	}
+	var evs []models.Event
+	if err := r.db.WithContext(ctx).Scopes(eventDetails).
+		Where("id IN ?", ids).Find(&evs).Error; err != nil {
+		return nil, 0, fmt.Errorf("eventRepo.Search load: %w", err)
+	}
+	byID := make(map[string]models.Event, len(evs))
+	for _, ev := range evs {
+		byID[ev.ID] = ev
+	}
+	matches := make([]models.EventMatch, 0, len(rows))
+	for _, row := range rows {
+		ev, ok := byID[row.EventID]
+		if !ok {
+			continue // deleted between the two queries
+		}
+		matches = append(matches, models.EventMatch{
+			Event: ev, Rank: row.Rank, Title: markup(row.Title), Snippet: markup(row.Snippet),
+		})
+	}
+	return matches, total, nil
+}
+
+// maxSearchTerms bounds the query handed to the search engine.
+const maxSearchTerms = 8
+
+// searchTerms splits query into lower-case words of letters and digits. Both
+// engines have their own query syntax; passing only bare words means user
+// input can never be parsed as an operator.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	return words
+}
+
+// The engines wrap matches in these markers; markup swaps them for <mark>
+// after escaping the text, so event text is never returned as raw HTML.
+const (
+	markOpen  = "⟦"
+	markClose = "⟧"
+)
+
+func markup(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, markOpen, "<mark>")
+	return strings.ReplaceAll(s, markClose, "</mark>")
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+type IdempotencyRepository interface {
+	// Reserve inserts rec unless a live record already holds (UserID, Key).
+	// Returns false when the key is taken. Expired records are replaced.
+	Reserve(ctx context.Context, rec *models.IdempotencyKey) (bool, error)
+	Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error)
+	// Complete stores the response of the request that reserved the key.
+	Complete(ctx context.Context, userID, key string, status int, body []byte) error
+	// Release drops a reservation so the request can be retried from scratch.
+	Release(ctx context.Context, userID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct{ db *gorm.DB }
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Reserve relies on the (user_id, idem_key) primary key: of two concurrent
+// requests with the same key — on any node — exactly one insert wins.
+func (r *idempotencyRepository) Reserve(ctx context.Context, rec *models.IdempotencyKey) (bool, error) {
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND idem_key = ? AND expires_at < ?", rec.UserID, rec.Key, time.Now()).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return false, fmt.Errorf("idemRepo.Reserve expire: %w", err)
+	}
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+	if res.Error != nil {
+		return false, fmt.Errorf("idemRepo.Reserve: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *idempotencyRepository) Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	var rec models.IdempotencyKey
+	if err := r.db.WithContext(ctx).First(&rec, "user_id = ? AND idem_key = ?", userID, key).Error; err != nil {
+		return nil, fmt.Errorf("idemRepo.Find: %w", err)
+	}
+	return &rec, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, userID, key string, status int, body []byte) error {
+	err := r.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND idem_key = ?", userID, key).
+		Updates(map[string]interface{}{"status_code": status, "response_body": body}).Error
+	if err != nil {
+		return fmt.Errorf("idemRepo.Complete: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, userID, key string) error {
+	err := r.db.WithContext(ctx).Where("user_id = ? AND idem_key = ?", userID, key).
+		Delete(&models.IdempotencyKey{}).Error
+	if err != nil {
+		return fmt.Errorf("idemRepo.Release: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("idemRepo.DeleteExpired: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+// MediaRepository stores event image records; the files themselves live in
+// a storage.BlobStore. Methods that take tx run on that transaction.
+type MediaRepository interface {
+	Create(tx *gorm.DB, m *models.EventMedia) error
+	Delete(tx *gorm.DB, id string) error
+	// FindByID returns eventID's media with this id, or gorm.ErrRecordNotFound.
+	FindByID(ctx context.Context, eventID, id string) (*models.EventMedia, error)
+	// FindCover returns eventID's cover inside tx, or gorm.ErrRecordNotFound.
+	FindCover(tx *gorm.DB, eventID string) (*models.EventMedia, error)
+	// CountGallery counts eventID's gallery images inside tx.
+	CountGallery(tx *gorm.DB, eventID string) (int64, error)
+}
+
+type mediaRepository struct{ db *gorm.DB }
+
+func NewMediaRepository(db *gorm.DB) MediaRepository { return &mediaRepository{db: db} }
+
+func (r *mediaRepository) Create(tx *gorm.DB, m *models.EventMedia) error {
+	if err := tx.Create(m).Error; err != nil {
+		return fmt.Errorf("mediaRepo.Create: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) Delete(tx *gorm.DB, id string) error {
+	if err := tx.Delete(&models.EventMedia{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("mediaRepo.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) FindByID(ctx context.Context, eventID, id string) (*models.EventMedia, error) {
+	var m models.EventMedia
+	if err := r.db.WithContext(ctx).First(&m, "id = ? AND event_id = ?", id, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mediaRepository) FindCover(tx *gorm.DB, eventID string) (*models.EventMedia, error) {
+	var m models.EventMedia
+	if err := tx.First(&m, "event_id = ? AND kind = ?", eventID, models.MediaCover).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mediaRepository) CountGallery(tx *gorm.DB, eventID string) (int64, error) {
+	var n int64
+	err := tx.Model(&models.EventMedia{}).
+		Where("event_id = ? AND kind = ?", eventID, models.MediaGallery).
+		Count(&n).Error
+	if err != nil {
+		return 0, fmt.Errorf("mediaRepo.CountGallery: %w", err)
+	}
+	return n, nil
+}
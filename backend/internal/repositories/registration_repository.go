@@ -1,16 +1,50 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
+	"time"
+
 	"gorm.io/gorm"
 	"github.com/Amrutavarshini24/Eventregistration/internal/models"
 )
 
+// RegistrationRepository methods that take tx run on that transaction, which
+// carries the caller's context.
 type RegistrationRepository interface {
+	// Create inserts reg and the first entry of its status history.
 	Create(tx *gorm.DB, reg *models.Registration) error
-	FindByUserAndEvent(userID, eventID string) (*models.Registration, error)
-	FindByEvent(eventID string) ([]models.Registration, error)
-	FindByUser(userID string) ([]models.Registration, error)
+	// UpdateStatus moves change.RegistrationID from change.FromStatus to
+	// change.ToStatus inside tx and records change in the status history.
+	// Returns false when the row is no longer in the expected status. It does
+	// not check the lifecycle; callers validate the transition first.
+	UpdateStatus(tx *gorm.DB, change *models.RegistrationStatusChange) (bool, error)
+	// Transfer moves a confirmed registration from one user to another inside
+	// tx and records the hand-over. Returns false when fromUserID no longer
+	// holds it as confirmed.
+	Transfer(tx *gorm.DB, regID, fromUserID, toUserID string) (bool, error)
+	FindByID(ctx context.Context, id string) (*models.Registration, error)
+	FindByUserAndEvent(ctx context.Context, userID, eventID string) (*models.Registration, error)
+	// FindExpiredHolds returns pending holds whose expiry is before now.
+	FindExpiredHolds(ctx context.Context, now time.Time) ([]models.Registration, error)
+	// NextWaitlisted returns the oldest waitlisted registration in the queue
+	// for eventID and tierID (nil for untiered bookings) inside tx, or
+	// gorm.ErrRecordNotFound when the queue is empty.
+	NextWaitlisted(tx *gorm.DB, eventID string, tierID *string) (*models.Registration, error)
+	// WaitlistPosition returns reg's 1-based position in its queue.
+	WaitlistPosition(ctx context.Context, reg *models.Registration) (int, error)
+	// ListActive returns eventID's registrations in models.ActiveStatuses
+	// inside tx.
+	ListActive(tx *gorm.DB, eventID string) ([]models.Registration, error)
+	// CountActive counts eventID's registrations in models.ActiveStatuses
+	// inside tx.
+	CountActive(tx *gorm.DB, eventID string) (int64, error)
+	// SeatCounts sums the seats held by eventID's registrations (see
+	// models.SeatStatuses): the total and the share of each tier.
+	SeatCounts(ctx context.Context, eventID string) (int, map[string]int, error)
+	// FindByEvent returns the ticketed registrations for eventID: the attendee list.
+	FindByEvent(ctx context.Context, eventID string) ([]models.Registration, error)
+	FindByUser(ctx context.Context, userID string) ([]models.Registration, error)
 }
 
 type registrationRepository struct{ db *gorm.DB }
@@ -20,23 +54,123 @@ func NewRegistrationRepository(db *gorm.DB) RegistrationRepository {
 }
 
 func (r *registrationRepository) Create(tx *gorm.DB, reg *models.Registration) error {
-	return tx.Create(reg).Error
+	if err := tx.Create(reg).Error; err != nil {
+		return err
+	}
+	first := &models.RegistrationStatusChange{RegistrationID: reg.ID, ToStatus: reg.Status, ActorID: &reg.UserID}
+	if err := tx.Create(first).Error; err != nil {
+		return fmt.Errorf("regRepo.Create history: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus is a conditional UPDATE: the WHERE status = from clause makes a
+// second concurrent transition a no-op, the same way IncrementRegistered's
+// capacity guard does for seats.
+func (r *registrationRepository) UpdateStatus(tx *gorm.DB, change *models.RegistrationStatusChange) (bool, error) {
+	updates := map[string]interface{}{"status": change.ToStatus}
+	switch change.ToStatus {
+	case models.StatusCancelled:
+		updates["cancelled_at"] = time.Now()
+	case models.StatusCheckedIn:
+		updates["checked_in_at"] = time.Now()
+	}
+	res := tx.Model(&models.Registration{}).
+		Where("id = ? AND status = ?", change.RegistrationID, change.FromStatus).
+		Updates(updates)
+	if res.Error != nil {
+		return false, fmt.Errorf("regRepo.UpdateStatus: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	if err := tx.Create(change).Error; err != nil {
+		return false, fmt.Errorf("regRepo.UpdateStatus history: %w", err)
+	}
+	return true, nil
+}
+
+func (r *registrationRepository) Transfer(tx *gorm.DB, regID, fromUserID, toUserID string) (bool, error) {
+	res := tx.Model(&models.Registration{}).
+		Where("id = ? AND user_id = ? AND status = ?", regID, fromUserID, models.StatusConfirmed).
+		Update("user_id", toUserID)
+	if res.Error != nil {
+		return false, fmt.Errorf("regRepo.Transfer: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	rec := &models.RegistrationTransfer{RegistrationID: regID, FromUserID: fromUserID, ToUserID: toUserID}
+	if err := tx.Create(rec).Error; err != nil {
+		return false, fmt.Errorf("regRepo.Transfer history: %w", err)
+	}
+	return true, nil
+}
+
+func (r *registrationRepository) FindByID(ctx context.Context, id string) (*models.Registration, error) {
+	var reg models.Registration
+	if err := r.db.WithContext(ctx).Preload("Attendees").First(&reg, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+func (r *registrationRepository) FindExpiredHolds(ctx context.Context, now time.Time) ([]models.Registration, error) {
+	var regs []models.Registration
+	err := r.db.WithContext(ctx).Where("status = ? AND hold_expires_at < ?", models.StatusPending, now).
+		Find(&regs).Error
+	if err != nil {
+		return nil, fmt.Errorf("regRepo.FindExpiredHolds: %w", err)
+	}
+	return regs, nil
+}
+
+func (r *registrationRepository) FindByUserAndEvent(ctx context.Context, userID, eventID string) (*models.Registration, error) {
+	var reg models.Registration
+	err := r.db.WithContext(ctx).Where("user_id = ? AND event_id = ? AND status IN ?",
+		userID, eventID, models.ActiveStatuses).First(&reg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reg, nil
 }
 
-func (r *registrationRepository) FindByUserAndEvent(userID, eventID string) (*models.Registration, error) {
+func (r *registrationRepository) NextWaitlisted(tx *gorm.DB, eventID string, tierID *string) (*models.Registration, error) {
 	var reg models.Registration
-	err := r.db.Where("user_id = ? AND event_id = ? AND status = ?",
-		userID, eventID, models.StatusConfirmed).First(&reg).Error
+	err := sameTier(tx, tierID).
+		Where("event_id = ? AND status = ?", eventID, models.StatusWaitlisted).
+		Order("created_at asc, id asc").
+		First(&reg).Error
 	if err != nil {
 		return nil, err
 	}
 	return &reg, nil
 }
 
-func (r *registrationRepository) FindByEvent(eventID string) ([]models.Registration, error) {
+func (r *registrationRepository) WaitlistPosition(ctx context.Context, reg *models.Registration) (int, error) {
+	var ahead int64
+	err := sameTier(r.db.WithContext(ctx).Model(&models.Registration{}), reg.TierID).
+		Where("event_id = ? AND status = ?", reg.EventID, models.StatusWaitlisted).
+		Where("created_at < ? OR (created_at = ? AND id < ?)", reg.CreatedAt, reg.CreatedAt, reg.ID).
+		Count(&ahead).Error
+	if err != nil {
+		return 0, fmt.Errorf("regRepo.WaitlistPosition: %w", err)
+	}
+	return int(ahead) + 1, nil
+}
+
+// sameTier scopes q to registrations for tierID; nil means untiered.
+func sameTier(q *gorm.DB, tierID *string) *gorm.DB {
+	if tierID == nil {
+		return q.Where("tier_id IS NULL")
+	}
+	return q.Where("tier_id = ?", *tierID)
+}
+
+func (r *registrationRepository) FindByEvent(ctx context.Context, eventID string) ([]models.Registration, error) {
 	var regs []models.Registration
-	err := r.db.Preload("User").
-		Where("event_id = ? AND status = ?", eventID, models.StatusConfirmed).
+	err := r.db.WithContext(ctx).Preload("User").Preload("Tier").Preload("Attendees").
+		Where("event_id = ? AND status IN ?", eventID, models.TicketedStatuses).
 		Find(&regs).Error
 	if err != nil {
 		return nil, fmt.Errorf("regRepo.FindByEvent: %w", err)
@@ -44,13 +178,60 @@ func (r *registrationRepository) FindByEvent(eventID string) ([]models.Registrat
 	return regs, nil
 }
 
-func (r *registrationRepository) FindByUser(userID string) ([]models.Registration, error) {
+func (r *registrationRepository) FindByUser(ctx context.Context, userID string) ([]models.Registration, error) {
 	var regs []models.Registration
-	err := r.db.Preload("Event").Preload("Event.Organizer").
-		Where("user_id = ? AND status = ?", userID, models.StatusConfirmed).
+	err := r.db.WithContext(ctx).Preload("Event").Preload("Event.Organizer").Preload("Tier").Preload("Attendees").
+		Preload("Transfers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc") }).
+		Where("user_id = ? AND status IN ?", userID, models.ActiveStatuses).
 		Find(&regs).Error
 	if err != nil {
 		return nil, fmt.Errorf("regRepo.FindByUser: %w", err)
 	}
 	return regs, nil
 }
+
+func (r *registrationRepository) SeatCounts(ctx context.Context, eventID string) (int, map[string]int, error) {
+	var rows []struct {
+		TierID *string
+		Seats  int
+	}
+	err := r.db.WithContext(ctx).Model(&models.Registration{}).
+		Select("tier_id, COALESCE(SUM(quantity), 0) AS seats").
+		Where("event_id = ? AND status IN ?", eventID, models.SeatStatuses).
+		Group("tier_id").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, nil, fmt.Errorf("regRepo.SeatCounts: %w", err)
+	}
+	total, byTier := 0, make(map[string]int)
+	for _, row := range rows {
+		total += row.Seats
+		if row.TierID != nil {
+			byTier[*row.TierID] = row.Seats
+		}
+	}
+	return total, byTier, nil
+}
+
+func (r *registrationRepository) CountActive(tx *gorm.DB, eventID string) (int64, error) {
+	var n int64
+	err := tx.Model(&models.Registration{}).
+		Where("event_id = ? AND status IN ?", eventID, models.ActiveStatuses).
+		Count(&n).Error
+	if err != nil {
+		return 0, fmt.Errorf("regRepo.CountActive: %w", err)
+	}
+	return n, nil
+}
+
+func (r *registrationRepository) ListActive(tx *gorm.DB, eventID string) ([]models.Registration, error) {
+	var regs []models.Registration
+	err := tx.Where("event_id = ? AND status IN ?", eventID, models.ActiveStatuses).
+		Order("created_at asc").
+		Find(&regs).Error
+	if err != nil {
+		return nil, fmt.Errorf("regRepo.ListActive: %w", err)
+	}
+	return regs, nil
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+// StatsRepository aggregates registrations for the organizer dashboard. All
+// counting is done in SQL; booking and cancellation times come from
+// registration_status_changes, so a hold confirmed on Tuesday counts as a
+// Tuesday booking.
+type StatsRepository interface {
+	// EventStats returns the counts of every event q selects, soonest first.
+	// Percentages and the sell-out time are left to the caller.
+	EventStats(ctx context.Context, q *models.StatsQuery) ([]models.EventStats, error)
+	// Timeline buckets the bookings and cancellations of the events q selects
+	// by q.Bucket. Empty buckets are left out.
+	Timeline(ctx context.Context, q *models.StatsQuery) ([]models.StatsBucket, error)
+}
+
+type statsRepository struct{ db *gorm.DB }
+
+func NewStatsRepository(db *gorm.DB) StatsRepository { return &statsRepository{db: db} }
+
+// events narrows a query joined to "events e" to the events q selects.
+func (r *statsRepository) events(q *models.StatsQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("e.deleted_at IS NULL")
+		if q.OrganizerID != "" {
+			db = db.Where("(e.organizer_id = ? OR e.id IN (?))", q.OrganizerID,
+				r.db.Table("event_collaborators").Select("event_id").Where("user_id = ?", q.OrganizerID))
+		}
+		if q.EventID != "" {
+			db = db.Where("e.id = ?", q.EventID)
+		}
+		return db
+	}
+}
+
+func (r *statsRepository) EventStats(ctx context.Context, q *models.StatsQuery) ([]models.EventStats, error) {
+	var evs []struct {
+		ID          string
+		Title       string
+		Status      models.EventStatus
+		EventDate   time.Time
+		Capacity    int64
+		SalesOpenAt *time.Time
+		CreatedAt   time.Time
+	}
+	err := r.db.WithContext(ctx).Table("events e").Scopes(r.events(q)).
+		Select("e.id, e.title, e.status, e.event_date, e.capacity, e.sales_open_at, e.created_at").
+		Order("e.event_date ASC, e.id ASC").Scan(&evs).Error
+	if err != nil {
+		return nil, fmt.Errorf("statsRepo.EventStats: %w", err)
+	}
+	if len(evs) == 0 {
+		return nil, nil
+	}
+
+	var byStatus []struct {
+		EventID string
+		Status  models.RegistrationStatus
+		Seats   int64
+	}
+	err = r.db.WithContext(ctx).Table("registrations r").
+		Joins("JOIN events e ON e.id = r.event_id").Scopes(r.events(q)).
+		Select("r.event_id, r.status, COALESCE(SUM(r.quantity), 0) AS seats").
+		Group("r.event_id, r.status").Scan(&byStatus).Error
+	if err != nil {
+		return nil, fmt.Errorf("statsRepo.EventStats by status: %w", err)
+	}
+
+	lastSale, err := utcText(r.db, "c.created_at", "second")
+	if err != nil {
+		return nil, err
+	}
+	var flows []struct {
+		EventID        string
+		Bookings       int64
+		Cancellations  int64
+		CancelledSeats int64
+		LastSaleAt     *string
+	}
+	err = r.db.WithContext(ctx).Table("registration_status_changes c").
+		Joins("JOIN registrations r ON r.id = c.registration_id").
+		Joins("JOIN events e ON e.id = r.event_id").Scopes(r.events(q)).
+		Select(`r.event_id,
+			SUM(CASE WHEN c.to_status = ? THEN 1 ELSE 0 END) AS bookings,
+			SUM(CASE WHEN c.to_status = ? AND c.from_status = ? THEN 1 ELSE 0 END) AS cancellations,
+			SUM(CASE WHEN c.to_status = ? AND c.from_status = ? THEN r.quantity ELSE 0 END) AS cancelled_seats,
+			MAX(CASE WHEN c.to_status = ? AND r.status IN ? THEN `+lastSale+` END) AS last_sale_at`,
+			models.StatusConfirmed,
+			models.StatusCancelled, models.StatusConfirmed,
+			models.StatusCancelled, models.StatusConfirmed,
+			models.StatusConfirmed, models.TicketedStatuses).
+		Group("r.event_id").Scan(&flows).Error
+	if err != nil {
+		return nil, fmt.Errorf("statsRepo.EventStats changes: %w", err)
+	}
+
+	out := make([]models.EventStats, len(evs))
+	index := make(map[string]*models.EventStats, len(evs))
+	for i, ev := range evs {
+		out[i] = models.EventStats{
+			EventID: ev.ID, Title: ev.Title, Status: ev.Status, EventDate: ev.EventDate,
+			SalesStart: ev.CreatedAt, Capacity: ev.Capacity,
+		}
+		if ev.SalesOpenAt != nil {
+			out[i].SalesStart = *ev.SalesOpenAt
+		}
+		index[ev.ID] = &out[i]
+	}
+	for _, row := range byStatus {
+		s := index[row.EventID]
+		switch {
+		case row.Status.Ticketed():
+			s.SeatsSold += row.Seats
+			if row.Status == models.StatusCheckedIn {
+				s.CheckedIn = row.Seats
+			} else if row.Status == models.StatusNoShow {
+				s.NoShows = row.Seats
+			}
+		case row.Status == models.StatusPending:
+			s.SeatsHeld = row.Seats
+		case row.Status == models.StatusWaitlisted:
+			s.Waitlisted = row.Seats
+		}
+	}
+	for _, row := range flows {
+		s := index[row.EventID]
+		s.Bookings, s.Cancellations, s.CancelledSeats = row.Bookings, row.Cancellations, row.CancelledSeats
+		if row.LastSaleAt != nil {
+			t, err := time.Parse(time.RFC3339, *row.LastSaleAt)
+			if err != nil {
+				return nil, fmt.Errorf("statsRepo.EventStats: %w", err)
+			}
+			s.LastSaleAt = &t
+		}
+	}
+	return out, nil
+}
+
+func (r *statsRepository) Timeline(ctx context.Context, q *models.StatsQuery) ([]models.StatsBucket, error) {
+	bucket, err := utcText(r.db, "c.created_at", q.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	db := r.db.WithContext(ctx).Table("registration_status_changes c").
+		Joins("JOIN registrations r ON r.id = c.registration_id").
+		Joins("JOIN events e ON e.id = r.event_id").Scopes(r.events(q)).
+		Where("c.to_status = ? OR (c.to_status = ? AND c.from_status = ?)",
+			models.StatusConfirmed, models.StatusCancelled, models.StatusConfirmed)
+	if !q.From.IsZero() {
+		db = db.Where("c.created_at >= ?", q.From)
+	}
+	if !q.To.IsZero() {
+		db = db.Where("c.created_at < ?", q.To)
+	}
+	var rows []struct {
+		Bucket        string
+		Bookings      int64
+		Seats         int64
+		Cancellations int64
+	}
+	err = db.Select(bucket+` AS bucket,
+			SUM(CASE WHEN c.to_status = ? THEN 1 ELSE 0 END) AS bookings,
+			SUM(CASE WHEN c.to_status = ? THEN r.quantity ELSE 0 END) AS seats,
+			SUM(CASE WHEN c.to_status = ? THEN 1 ELSE 0 END) AS cancellations`,
+		models.StatusConfirmed, models.StatusConfirmed, models.StatusCancelled).
+		Group("bucket").Order("bucket ASC").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("statsRepo.Timeline: %w", err)
+	}
+	out := make([]models.StatsBucket, len(rows))
+	for i, row := range rows {
+		start, err := time.Parse(time.RFC3339, row.Bucket)
+		if err != nil {
+			return nil, fmt.Errorf("statsRepo.Timeline: %w", err)
+		}
+		out[i] = models.StatsBucket{Start: start, Bookings: row.Bookings, Seats: row.Seats, Cancellations: row.Cancellations}
+	}
+	return out, nil
+}
+
+// utcText is SQL rendering the timestamp column col as RFC3339 in UTC,
+// truncated to unit (day, hour or second). Both databases can produce this
+// text and it sorts and groups like the time itself, which matters on SQLite:
+// it has no timestamp type and returns aggregates of times as plain strings.
+func utcText(db *gorm.DB, col, unit string) (string, error) {
+	switch name := db.Dialector.Name(); name {
+	case "postgres":
+		switch unit {
+		case "day", "hour", "second":
+		default:
+			return "", fmt.Errorf("statsRepo: unknown unit %q", unit)
+		}
+		return fmt.Sprintf(`to_char(date_trunc('%s', %s AT TIME ZONE 'UTC'), 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`, unit, col), nil
+	case "sqlite":
+		layout, ok := map[string]string{
+			"day":    "%Y-%m-%dT00:00:00Z",
+			"hour":   "%Y-%m-%dT%H:00:00Z",
+			"second": "%Y-%m-%dT%H:%M:%SZ",
+		}[unit]
+		if !ok {
+			return "", fmt.Errorf("statsRepo: unknown unit %q", unit)
+		}
+		return fmt.Sprintf("strftime('%s', %s)", layout, col), nil
+	default:
+		return "", fmt.Errorf("statsRepo: unsupported dialect %s", name)
+	}
+}
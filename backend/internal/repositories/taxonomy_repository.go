@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+// TaxonomyRepository stores categories and reports how categories and tags
+// are used. Event labels themselves are saved with the event (see
+// EventRepository.Create).
+type TaxonomyRepository interface {
+	CreateCategory(ctx context.Context, c *models.Category) error
+	// FindCategory returns the category with this slug or name, or
+	// gorm.ErrRecordNotFound.
+	FindCategory(ctx context.Context, slug, name string) (*models.Category, error)
+	// FindCategoriesBySlug returns the categories among slugs that exist.
+	FindCategoriesBySlug(ctx context.Context, slugs []string) ([]models.Category, error)
+	// CategoryCounts returns every category, by name, with its number of
+	// listed (non-draft) events.
+	CategoryCounts(ctx context.Context) ([]models.CategoryCount, error)
+	// PopularTags returns the limit tags on the most listed events.
+	PopularTags(ctx context.Context, limit int) ([]models.TagCount, error)
+}
+
+type taxonomyRepository struct{ db *gorm.DB }
+
+func NewTaxonomyRepository(db *gorm.DB) TaxonomyRepository { return &taxonomyRepository{db: db} }
+
+func (r *taxonomyRepository) CreateCategory(ctx context.Context, c *models.Category) error {
+	if err := r.db.WithContext(ctx).Create(c).Error; err != nil {
+		return fmt.Errorf("taxonomyRepo.CreateCategory: %w", err)
+	}
+	return nil
+}
+
+func (r *taxonomyRepository) FindCategory(ctx context.Context, slug, name string) (*models.Category, error) {
+	var c models.Category
+	if err := r.db.WithContext(ctx).Where("slug = ? OR name = ?", slug, name).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *taxonomyRepository) FindCategoriesBySlug(ctx context.Context, slugs []string) ([]models.Category, error) {
+	var cs []models.Category
+	if err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Find(&cs).Error; err != nil {
+		return nil, fmt.Errorf("taxonomyRepo.FindCategoriesBySlug: %w", err)
+	}
+	return cs, nil
+}
+
+// listedEvents is the join condition that counts an event: visible in the
+// list, so neither a draft nor deleted.
+const listedEvents = "e.id = %s.event_id AND e.status <> ? AND e.deleted_at IS NULL"
+
+func (r *taxonomyRepository) CategoryCounts(ctx context.Context) ([]models.CategoryCount, error) {
+	var rows []models.CategoryCount
+	err := r.db.WithContext(ctx).Table("categories c").
+		Select("c.*, COUNT(e.id) AS event_count").
+		Joins("LEFT JOIN event_categories ec ON ec.category_id = c.id").
+		Joins("LEFT JOIN events e ON "+fmt.Sprintf(listedEvents, "ec"), models.EventDraft).
+		Group("c.id").
+		Order("c.name asc").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("taxonomyRepo.CategoryCounts: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *taxonomyRepository) PopularTags(ctx context.Context, limit int) ([]models.TagCount, error) {
+	var rows []models.TagCount
+	err := r.db.WithContext(ctx).Table("event_tags et").
+		Select("et.tag_name AS name, COUNT(*) AS event_count").
+		Joins("JOIN events e ON "+fmt.Sprintf(listedEvents, "et"), models.EventDraft).
+		Group("et.tag_name").
+		Order("event_count desc, name asc").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("taxonomyRepo.PopularTags: %w", err)
+	}
+	return rows, nil
+}
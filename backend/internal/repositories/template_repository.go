@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+type TemplateRepository interface {
+	// Create saves t with its tiers. A name the organizer already uses
+	// fails with gorm.ErrDuplicatedKey.
+	Create(ctx context.Context, t *models.EventTemplate) error
+	// FindByID returns a template with its tiers in order, or
+	// gorm.ErrRecordNotFound.
+	FindByID(ctx context.Context, id string) (*models.EventTemplate, error)
+	// FindByName returns organizerID's template called name, or
+	// gorm.ErrRecordNotFound.
+	FindByName(ctx context.Context, organizerID, name string) (*models.EventTemplate, error)
+	// ListByOrganizer returns organizerID's templates by name.
+	ListByOrganizer(ctx context.Context, organizerID string) ([]models.EventTemplate, error)
+	// Delete removes a template and its tiers.
+	Delete(ctx context.Context, id string) error
+}
+
+type templateRepository struct{ db *gorm.DB }
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepository { return &templateRepository{db: db} }
+
+// templateTiers preloads a template's tiers in their saved order.
+func templateTiers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tiers", func(db *gorm.DB) *gorm.DB { return db.Order("position asc") })
+}
+
+func (r *templateRepository) Create(ctx context.Context, t *models.EventTemplate) error {
+	if err := r.db.WithContext(ctx).Create(t).Error; err != nil {
+		// Surface the unique-name violation the same way on every dialect.
+		if tr, ok := r.db.Dialector.(gorm.ErrorTranslator); ok {
+			err = tr.Translate(err)
+		}
+		return fmt.Errorf("templateRepo.Create: %w", err)
+	}
+	return nil
+}
+
+func (r *templateRepository) FindByID(ctx context.Context, id string) (*models.EventTemplate, error) {
+	var t models.EventTemplate
+	if err := r.db.WithContext(ctx).Scopes(templateTiers).First(&t, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("templateRepo.FindByID: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *templateRepository) FindByName(ctx context.Context, organizerID, name string) (*models.EventTemplate, error) {
+	var t models.EventTemplate
+	err := r.db.WithContext(ctx).Where("organizer_id = ? AND name = ?", organizerID, name).First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *templateRepository) ListByOrganizer(ctx context.Context, organizerID string) ([]models.EventTemplate, error) {
+	var ts []models.EventTemplate
+	err := r.db.WithContext(ctx).Scopes(templateTiers).
+		Where("organizer_id = ?", organizerID).Order("name asc").Find(&ts).Error
+	if err != nil {
+		return nil, fmt.Errorf("templateRepo.ListByOrganizer: %w", err)
+	}
+	return ts, nil
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.TemplateTier{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.EventTemplate{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("templateRepo.Delete: %w", err)
+	}
+	return nil
+}
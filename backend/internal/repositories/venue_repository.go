@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+)
+
+type VenueRepository interface {
+	Create(ctx context.Context, v *models.Venue) error
+	FindByID(ctx context.Context, id string) (*models.Venue, error)
+	// List returns every venue by name.
+	List(ctx context.Context) ([]models.Venue, error)
+}
+
+type venueRepository struct{ db *gorm.DB }
+
+func NewVenueRepository(db *gorm.DB) VenueRepository { return &venueRepository{db: db} }
+
+func (r *venueRepository) Create(ctx context.Context, v *models.Venue) error {
+	if err := r.db.WithContext(ctx).Create(v).Error; err != nil {
+		return fmt.Errorf("venueRepo.Create: %w", err)
+	}
+	return nil
+}
+
+func (r *venueRepository) FindByID(ctx context.Context, id string) (*models.Venue, error) {
+	var v models.Venue
+	if err := r.db.WithContext(ctx).First(&v, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("venueRepo.FindByID: %w", err)
+	}
+	return &v, nil
+}
+
+func (r *venueRepository) List(ctx context.Context) ([]models.Venue, error) {
+	var vs []models.Venue
+	if err := r.db.WithContext(ctx).Order("name asc").Find(&vs).Error; err != nil {
+		return nil, fmt.Errorf("venueRepo.List: %w", err)
+	}
+	return vs, nil
+}
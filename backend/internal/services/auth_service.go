@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -15,16 +16,16 @@ import (
 )
 
 type AuthService interface {
-	Register(req *models.RegisterRequest) (*models.User, error)
-	Login(req *models.LoginRequest) (string, *models.User, error)
+	Register(ctx context.Context, req *models.RegisterRequest) (*models.User, error)
+	Login(ctx context.Context, req *models.LoginRequest) (string, *models.User, error)
 }
 
 type authService struct{ userRepo repositories.UserRepository }
 
 func NewAuthService(r repositories.UserRepository) AuthService { return &authService{userRepo: r} }
 
-func (s *authService) Register(req *models.RegisterRequest) (*models.User, error) {
-	if _, err := s.userRepo.FindByEmail(req.Email); err == nil {
+func (s *authService) Register(ctx context.Context, req *models.RegisterRequest) (*models.User, error) {
+	if _, err := s.userRepo.FindByEmail(ctx, req.Email); err == nil {
 		return nil, errors.New("email already registered")
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("authSvc.Register lookup: %w", err)
@@ -40,14 +41,14 @@ func (s *authService) Register(req *models.RegisterRequest) (*models.User, error
 		role = "attendee"
 	}
 	user := &models.User{Name: req.Name, Email: req.Email, PasswordHash: string(hash), Role: role}
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("authSvc.Register create: %w", err)
 	}
 	return user, nil
 }
 
-func (s *authService) Login(req *models.LoginRequest) (string, *models.User, error) {
-	user, err := s.userRepo.FindByEmail(req.Email)
+func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (string, *models.User, error) {
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		return "", nil, errors.New("invalid email or password")
 	}
@@ -62,14 +63,20 @@ func (s *authService) Login(req *models.LoginRequest) (string, *models.User, err
 }
 
 func signJWT(user *models.User) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "dev_secret_please_change"
-	}
 	claims := jwt.MapClaims{
 		"sub":  user.ID,
 		"role": user.Role,
 		"exp":  time.Now().Add(24 * time.Hour).Unix(),
 	}
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret())
+}
+
+// jwtSecret is the HMAC key for auth tokens, and the root of ticketKey. It
+// must match the key middleware.AuthRequired verifies with.
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "dev_secret_please_change"
+	}
+	return []byte(secret)
 }
@@ -1,20 +1,24 @@
 // Package services — BookingService: the concurrency-safe seat reservation core.
 //
 // Three-layer defence against overbooking:
-//  1. Per-event sync.Mutex  — serialises same-node goroutines for the same event.
+//  1. Per-event lock        — locking.Locker: in-process mutex, Postgres
+//                             advisory lock or no-op, chosen by LOCK_BACKEND.
 //  2. DB transaction        — atomic read+write, auto-rollback on error.
 //  3. Conditional UPDATE    — WHERE registered < capacity is the ultimate net
 //                             (works even in multi-node / distributed deployments).
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
-	"sync"
+	"time"
 
 	"gorm.io/gorm"
 
+	"github.com/Amrutavarshini24/Eventregistration/internal/config"
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
 	"github.com/Amrutavarshini24/Eventregistration/internal/models"
 	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
 )
@@ -22,41 +26,123 @@ import (
 var (
 	ErrEventFull      = errors.New("event is fully booked")
 	ErrDuplicateBooking = errors.New("user has already registered for this event")
+	ErrRegistrationNotFound = errors.New("registration not found")
+	ErrInvalidQuantity = errors.New("invalid booking quantity")
+	ErrEventNotFound   = errors.New("event not found")
+	ErrSalesClosed     = errors.New("ticket sales are not open for this event")
+	ErrEventNotPublished = errors.New("event is not published yet")
+	ErrEventCancelled  = errors.New("event has been cancelled")
+	ErrTierRequired    = errors.New("this event has ticket tiers; choose a tier_id")
+	ErrTierNotFound    = errors.New("ticket tier not found for this event")
+	ErrTierNotOnSale   = errors.New("ticket tier is not on sale")
+	ErrTierSoldOut     = errors.New("ticket tier is sold out")
+	ErrNotHeld         = errors.New("registration is not an active seat hold")
+	ErrHoldExpired     = errors.New("seat hold has expired")
+	ErrNotTransferable = errors.New("only confirmed registrations can be transferred")
+	ErrRecipientNotFound = errors.New("no user with that email")
+	ErrTimeout         = errors.New("timed out waiting for the event; please retry")
+	ErrSelfTransfer    = errors.New("cannot transfer a registration to yourself")
 )
 
 type BookingService interface {
-	Book(userID, eventID string) (*models.Registration, error)
-	GetEventRegistrations(eventID string) ([]models.Registration, error)
-	GetUserRegistrations(userID string) ([]models.Registration, error)
+	// Book reserves req.Quantity seats, all or nothing. When they don't fit and
+	// req.JoinWaitlist is set, the returned registration is waitlisted instead
+	// of ErrEventFull / ErrTierSoldOut.
+	Book(ctx context.Context, userID, eventID string, req *models.BookingRequest) (*models.Registration, error)
+	Cancel(ctx context.Context, userID, eventID string) (*models.Registration, error)
+	// Hold claims seats like Book but as a pending registration that must be
+	// confirmed before it expires. Holds never join the waitlist.
+	Hold(ctx context.Context, userID, eventID string, req *models.BookingRequest) (*models.Registration, error)
+	ConfirmHold(ctx context.Context, userID, regID string) (*models.Registration, error)
+	ReleaseHold(ctx context.Context, userID, regID string) (*models.Registration, error)
+	// Transfer hands userID's confirmed registration to the user with
+	// toEmail, who must not already be registered for the event.
+	Transfer(ctx context.Context, userID, regID, toEmail string) (*models.Registration, error)
+	// Refund marks a cancelled or no-show registration refunded. Only the
+	// event's organizer or a co-organizer may do this; a no-show's seats are
+	// released.
+	Refund(ctx context.Context, organizerID, regID string) (*models.Registration, error)
+	// ExpireHolds releases every hold past its expiry and reports how many.
+	ExpireHolds(ctx context.Context) (int, error)
+	// RunHoldSweeper calls ExpireHolds every interval until ctx is done.
+	RunHoldSweeper(ctx context.Context, interval time.Duration)
+	// GetEventRegistrations lists eventID's attendees for its organizer and
+	// its co-organizers and staff (ErrNotEventOrganizer otherwise).
+	GetEventRegistrations(ctx context.Context, userID, eventID string) ([]models.Registration, error)
+	GetUserRegistrations(ctx context.Context, userID string) ([]models.Registration, error)
 }
 
 type bookingService struct {
+	seatLedger
 	db         *gorm.DB
-	regRepo    repositories.RegistrationRepository
-	evtRepo    repositories.EventRepository
-	eventLocks sync.Map // eventID → *sync.Mutex
+	userRepo   repositories.UserRepository
+	holdTTL    time.Duration
 }
 
-func NewBookingService(db *gorm.DB, r repositories.RegistrationRepository, e repositories.EventRepository) BookingService {
-	return &bookingService{db: db, regRepo: r, evtRepo: e}
+// NewBookingService reads HOLD_TTL (a Go duration, default 10m) for the
+// lifetime of seat holds.
+func NewBookingService(db *gorm.DB, r repositories.RegistrationRepository, e repositories.EventRepository, u repositories.UserRepository, l locking.Locker) BookingService {
+	return &bookingService{
+		seatLedger: seatLedger{regRepo: r, evtRepo: e, locker: l},
+		db: db, userRepo: u,
+		holdTTL: config.Duration("HOLD_TTL", 10*time.Minute),
+	}
+}
+
+// ctxErr maps a request deadline that expired inside a query or transaction
+// to ErrTimeout, so callers see the same error as a lock timeout.
+func ctxErr(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrTimeout, err)
+	}
+	return err
+}
+
+// Book reserves seats for userID in eventID. req may be nil.
+func (s *bookingService) Book(ctx context.Context, userID, eventID string, req *models.BookingRequest) (*models.Registration, error) {
+	reg, err := s.reserve(ctx, userID, eventID, req, nil)
+	return reg, ctxErr(ctx, err)
 }
 
-func (s *bookingService) mu(eventID string) *sync.Mutex {
-	mu, _ := s.eventLocks.LoadOrStore(eventID, &sync.Mutex{})
-	return mu.(*sync.Mutex)
+func (s *bookingService) Hold(ctx context.Context, userID, eventID string, req *models.BookingRequest) (*models.Registration, error) {
+	expires := time.Now().Add(s.holdTTL)
+	reg, err := s.reserve(ctx, userID, eventID, req, &expires)
+	return reg, ctxErr(ctx, err)
 }
 
-// Book reserves a seat for userID in eventID.
-func (s *bookingService) Book(userID, eventID string) (*models.Registration, error) {
-	// ── Layer 1: per-event mutex ──────────────────────────────────────────────
-	mu := s.mu(eventID)
-	mu.Lock()
-	defer mu.Unlock()
+// reserve is the shared body of Book and Hold. A non-nil holdUntil creates a
+// pending hold instead of a confirmed booking and disables the waitlist.
+func (s *bookingService) reserve(ctx context.Context, userID, eventID string, req *models.BookingRequest, holdUntil *time.Time) (*models.Registration, error) {
+	if req == nil {
+		req = &models.BookingRequest{}
+	}
+	qty, attendees, err := seatsFor(req)
+	if err != nil {
+		return nil, err
+	}
+	// Turn away unbookable events before queueing for the lock.
+	if _, err := s.bookable(ctx, userID, eventID, req.TierID); err != nil {
+		return nil, err
+	}
+
+	// ── Layer 1: per-event lock ───────────────────────────────────────────────
+	unlock, err := s.lock(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// The event may have been cancelled, unpublished or taken off sale while
+	// we waited; only this check, under the lock, is authoritative.
+	tierID, err := s.bookable(ctx, userID, eventID, req.TierID)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Printf("USER %s attempted booking for event %s", userID, eventID)
 
 	// ── Duplicate guard ───────────────────────────────────────────────────────
-	if existing, err := s.regRepo.FindByUserAndEvent(userID, eventID); err == nil && existing != nil {
+	if existing, err := s.regRepo.FindByUserAndEvent(ctx, userID, eventID); err == nil && existing != nil {
 		log.Printf("BOOKING FAILED — DUPLICATE | user=%s event=%s", userID, eventID)
 		return nil, ErrDuplicateBooking
 	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -65,31 +151,418 @@ func (s *bookingService) Book(userID, eventID string) (*models.Registration, err
 
 	// ── Layers 2 & 3: transaction + conditional UPDATE ────────────────────────
 	var reg *models.Registration
-	txErr := s.db.Transaction(func(tx *gorm.DB) error {
-		_, ok, err := s.evtRepo.IncrementRegistered(tx, eventID)
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := s.claimSeats(tx, eventID, tierID, qty)
+		if errors.Is(err, ErrEventFull) || errors.Is(err, ErrTierSoldOut) {
+			if !req.JoinWaitlist || holdUntil != nil {
+				log.Printf("BOOKING FAILED — FULL | user=%s event=%s", userID, eventID)
+				return err
+			}
+			reg = &models.Registration{
+				UserID: userID, EventID: eventID, Status: models.StatusWaitlisted,
+				Quantity: qty, TierID: tierID, Attendees: attendees,
+			}
+			if err := s.regRepo.Create(tx, reg); err != nil {
+				return err
+			}
+			log.Printf("BOOKING WAITLISTED | user=%s event=%s reg=%s", userID, eventID, reg.ID)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		reg = &models.Registration{
+			UserID: userID, EventID: eventID, Status: models.StatusConfirmed,
+			Quantity: qty, TierID: tierID, Attendees: attendees,
+		}
+		if holdUntil != nil {
+			reg.Status, reg.HoldExpiresAt = models.StatusPending, holdUntil
+		}
+		if err := s.regRepo.Create(tx, reg); err != nil {
+			return err
+		}
+		log.Printf("SEAT RESERVED SUCCESSFULLY | user=%s event=%s reg=%s seats=%d status=%s", userID, eventID, reg.ID, qty, reg.Status)
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	if err := s.fillWaitlistPosition(ctx, reg); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// bookable loads eventID and checks it is published and on sale, returning
+// the tier to book (nil when the event has no tiers).
+func (s *bookingService) bookable(ctx context.Context, userID, eventID, tierID string) (*string, error) {
+	ev, err := s.evtRepo.FindByID(ctx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEventNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	switch ev.Status {
+	case models.EventDraft:
+		return nil, ErrEventNotPublished
+	case models.EventCancelled:
+		return nil, ErrEventCancelled
+	}
+	now := time.Now()
+	if !ev.OnSaleAt(now) {
+		log.Printf("BOOKING FAILED — SALES CLOSED | user=%s event=%s", userID, eventID)
+		return nil, ErrSalesClosed
+	}
+	return pickTier(ev, tierID, now)
+}
+
+// Cancel releases userID's active registration for eventID. The status change,
+// the seat release and any waitlist promotion share one transaction under the
+// same per-event lock as Book, so a freed seat can never be claimed twice.
+func (s *bookingService) Cancel(ctx context.Context, userID, eventID string) (reg *models.Registration, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	unlock, err := s.lock(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	reg, err = s.regRepo.FindByUserAndEvent(ctx, userID, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRegistrationNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("bookingSvc.Cancel lookup: %w", err)
+	}
+	if reg.Status == models.StatusCheckedIn {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { return s.cancelTx(tx, reg, userID, "") })
+	if txErr != nil {
+		return nil, txErr
+	}
+	log.Printf("BOOKING CANCELLED | user=%s event=%s reg=%s", userID, eventID, reg.ID)
+	return reg, nil
+}
+
+// cancelTx moves reg to cancelled on behalf of actorID ("" for the system)
+// and, if it held seats, releases them and promotes the waitlist. The caller
+// holds the event lock.
+func (s *bookingService) cancelTx(tx *gorm.DB, reg *models.Registration, actorID, reason string) error {
+	heldSeat := reg.Status.HoldsSeat()
+	ok, err := transition(tx, s.regRepo, reg, models.StatusCancelled, actorID, reason)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRegistrationNotFound // changed by a concurrent request
+	}
+	if !heldSeat {
+		return nil // waitlisted: no seat to release
+	}
+	if err := s.releaseSeats(tx, reg.EventID, reg.TierID, reg.Quantity); err != nil {
+		return err
+	}
+	return s.promoteWaitlist(tx, reg.EventID, reg.TierID)
+}
+
+// ConfirmHold turns userID's pending hold into a confirmed booking. A hold
+// that has already expired is released on the spot.
+func (s *bookingService) ConfirmHold(ctx context.Context, userID, regID string) (reg *models.Registration, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	reg, err = s.ownedRegistration(ctx, userID, regID)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := s.lock(ctx, reg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if reg, err = s.ownedRegistration(ctx, userID, regID); err != nil { // re-read under the lock
+		return nil, err
+	}
+	if reg.Status != models.StatusPending {
+		return nil, ErrNotHeld
+	}
+	if reg.HoldExpiresAt != nil && time.Now().After(*reg.HoldExpiresAt) {
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { return s.cancelTx(tx, reg, "", "hold expired") }); err != nil {
+			return nil, err
+		}
+		log.Printf("HOLD EXPIRED | user=%s event=%s reg=%s", userID, reg.EventID, reg.ID)
+		return nil, ErrHoldExpired
+	}
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := transition(tx, s.regRepo, reg, models.StatusConfirmed, userID, "")
 		if err != nil {
 			return err
 		}
 		if !ok {
-			log.Printf("BOOKING FAILED — FULL | user=%s event=%s", userID, eventID)
-			return ErrEventFull
+			return ErrNotHeld
 		}
-		reg = &models.Registration{UserID: userID, EventID: eventID, Status: models.StatusConfirmed}
-		if err := s.regRepo.Create(tx, reg); err != nil {
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	log.Printf("HOLD CONFIRMED | user=%s event=%s reg=%s", userID, reg.EventID, reg.ID)
+	return reg, nil
+}
+
+// ReleaseHold gives userID's pending hold back before it expires.
+func (s *bookingService) ReleaseHold(ctx context.Context, userID, regID string) (reg *models.Registration, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	reg, err = s.ownedRegistration(ctx, userID, regID)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := s.lock(ctx, reg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if reg, err = s.ownedRegistration(ctx, userID, regID); err != nil {
+		return nil, err
+	}
+	if reg.Status != models.StatusPending {
+		return nil, ErrNotHeld
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { return s.cancelTx(tx, reg, userID, "hold released") }); err != nil {
+		return nil, err
+	}
+	log.Printf("HOLD RELEASED | user=%s event=%s reg=%s", userID, reg.EventID, reg.ID)
+	return reg, nil
+}
+
+func (s *bookingService) ExpireHolds(ctx context.Context) (int, error) {
+	stale, err := s.regRepo.FindExpiredHolds(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	for i := range stale {
+		reg := &stale[i]
+		err := func() error {
+			unlock, err := s.lock(ctx, reg.EventID)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+			return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { return s.cancelTx(tx, reg, "", "hold expired") })
+		}()
+		if errors.Is(err, ErrRegistrationNotFound) {
+			continue // confirmed or released since we listed it
+		} else if err != nil {
+			return released, err
+		}
+		log.Printf("HOLD EXPIRED | user=%s event=%s reg=%s", reg.UserID, reg.EventID, reg.ID)
+		released++
+	}
+	return released, nil
+}
+
+func (s *bookingService) RunHoldSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpireHolds(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("hold sweeper: %v", err)
+			}
+		}
+	}
+}
+
+// Transfer runs under the event lock so the recipient's duplicate check and
+// the hand-over cannot interleave with a booking by the recipient; the
+// partial unique index backs this up across nodes. Seat counts are untouched.
+func (s *bookingService) Transfer(ctx context.Context, userID, regID, toEmail string) (reg *models.Registration, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	reg, err = s.ownedRegistration(ctx, userID, regID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.userRepo.FindByEmail(ctx, toEmail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecipientNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if to.ID == userID {
+		return nil, ErrSelfTransfer
+	}
+
+	unlock, err := s.lock(ctx, reg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if reg, err = s.ownedRegistration(ctx, userID, regID); err != nil { // re-read under the lock
+		return nil, err
+	}
+	if reg.Status != models.StatusConfirmed {
+		return nil, ErrNotTransferable
+	}
+	if existing, err := s.regRepo.FindByUserAndEvent(ctx, to.ID, reg.EventID); err == nil && existing != nil {
+		return nil, ErrDuplicateBooking
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("bookingSvc.Transfer lookup: %w", err)
+	}
+
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := s.regRepo.Transfer(tx, reg.ID, userID, to.ID)
+		if err != nil {
 			return err
 		}
-		log.Printf("SEAT RESERVED SUCCESSFULLY | user=%s event=%s reg=%s", userID, eventID, reg.ID)
+		if !ok {
+			return ErrNotTransferable
+		}
 		return nil
 	})
 	if txErr != nil {
 		return nil, txErr
 	}
+	log.Printf("REGISTRATION TRANSFERRED | from=%s to=%s event=%s reg=%s", userID, to.ID, reg.EventID, reg.ID)
+	return s.regRepo.FindByID(ctx, reg.ID)
+}
+
+func (s *bookingService) Refund(ctx context.Context, organizerID, regID string) (reg *models.Registration, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	reg, err = s.regRepo.FindByID(ctx, regID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRegistrationNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("bookingSvc.Refund lookup: %w", err)
+	}
+	if _, err := managedEvent(ctx, s.evtRepo, organizerID, reg.EventID, accessEdit); err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.lock(ctx, reg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if reg, err = s.regRepo.FindByID(ctx, regID); err != nil { // re-read under the lock
+		return nil, fmt.Errorf("bookingSvc.Refund lookup: %w", err)
+	}
+	heldSeat := reg.Status.HoldsSeat()
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := transition(tx, s.regRepo, reg, models.StatusRefunded, organizerID, "")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrRegistrationNotFound // changed by a concurrent request
+		}
+		if !heldSeat {
+			return nil
+		}
+		return s.releaseSeats(tx, reg.EventID, reg.TierID, reg.Quantity)
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	log.Printf("REGISTRATION REFUNDED | organizer=%s event=%s reg=%s", organizerID, reg.EventID, reg.ID)
+	return reg, nil
+}
+
+// ownedRegistration loads regID and hides it unless userID owns it.
+func (s *bookingService) ownedRegistration(ctx context.Context, userID, regID string) (*models.Registration, error) {
+	reg, err := s.regRepo.FindByID(ctx, regID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRegistrationNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("bookingSvc lookup: %w", err)
+	}
+	if reg.UserID != userID {
+		return nil, ErrRegistrationNotFound
+	}
 	return reg, nil
 }
 
-func (s *bookingService) GetEventRegistrations(id string) ([]models.Registration, error) {
-	return s.regRepo.FindByEvent(id)
+// pickTier resolves the tier a booking draws from. Events without tiers take
+// untiered bookings only; events with tiers require one that is on sale.
+func pickTier(ev *models.Event, tierID string, now time.Time) (*string, error) {
+	if len(ev.Tiers) == 0 {
+		if tierID != "" {
+			return nil, ErrTierNotFound
+		}
+		return nil, nil
+	}
+	if tierID == "" {
+		return nil, ErrTierRequired
+	}
+	for i := range ev.Tiers {
+		if t := &ev.Tiers[i]; t.ID == tierID {
+			if !t.OnSaleAt(now) {
+				return nil, ErrTierNotOnSale
+			}
+			return &t.ID, nil
+		}
+	}
+	return nil, ErrTierNotFound
+}
+
+// seatsFor normalises the quantity and attendee names of a booking request.
+func seatsFor(req *models.BookingRequest) (int, []models.RegistrationAttendee, error) {
+	qty := req.Quantity
+	if qty == 0 {
+		qty = len(req.Attendees)
+	}
+	if qty == 0 {
+		qty = 1
+	}
+	if qty < 1 || qty > models.MaxSeatsPerBooking {
+		return 0, nil, fmt.Errorf("%w: must be between 1 and %d", ErrInvalidQuantity, models.MaxSeatsPerBooking)
+	}
+	if len(req.Attendees) == 0 && qty > 1 {
+		return 0, nil, fmt.Errorf("%w: name an attendee for each of the %d seats", ErrInvalidQuantity, qty)
+	}
+	if len(req.Attendees) > 0 && len(req.Attendees) != qty {
+		return 0, nil, fmt.Errorf("%w: %d attendees named for %d seats", ErrInvalidQuantity, len(req.Attendees), qty)
+	}
+	attendees := make([]models.RegistrationAttendee, len(req.Attendees))
+	for i, a := range req.Attendees {
+		attendees[i] = models.RegistrationAttendee{Seat: i + 1, Name: a.Name}
+	}
+	return qty, attendees, nil
+}
+
+// fillWaitlistPosition sets reg.WaitlistPosition when reg is waitlisted.
+func (s *bookingService) fillWaitlistPosition(ctx context.Context, reg *models.Registration) error {
+	if reg.Status != models.StatusWaitlisted {
+		return nil
+	}
+	pos, err := s.regRepo.WaitlistPosition(ctx, reg)
+	if err != nil {
+		return err
+	}
+	reg.WaitlistPosition = pos
+	return nil
 }
-func (s *bookingService) GetUserRegistrations(id string) ([]models.Registration, error) {
-	return s.regRepo.FindByUser(id)
+
+func (s *bookingService) GetEventRegistrations(ctx context.Context, userID, eventID string) ([]models.Registration, error) {
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessCheckIn); err != nil {
+		return nil, err
+	}
+	return s.regRepo.FindByEvent(ctx, eventID)
+}
+func (s *bookingService) GetUserRegistrations(ctx context.Context, id string) ([]models.Registration, error) {
+	regs, err := s.regRepo.FindByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range regs {
+		if err := s.fillWaitlistPosition(ctx, &regs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return regs, nil
 }
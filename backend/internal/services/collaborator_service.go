@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+var (
+	ErrInviteeNotFound      = errors.New("no user with this email")
+	ErrInviteOrganizer      = errors.New("the event's organizer cannot be a collaborator")
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+)
+
+// CollaboratorService manages who else may work on an event and in what
+// role. What each role allows is decided by accessTo.
+type CollaboratorService interface {
+	// Invite gives the user with req.Email a role on eventID, or changes the
+	// role they already have. Needs co-organizer access.
+	Invite(ctx context.Context, userID, eventID string, req *models.InviteCollaboratorRequest) (*models.EventCollaborator, error)
+	// List returns eventID's collaborators. Needs viewer access.
+	List(ctx context.Context, userID, eventID string) ([]models.EventCollaborator, error)
+	// Remove takes memberID off eventID. Needs co-organizer access, except
+	// that collaborators may always remove themselves.
+	Remove(ctx context.Context, userID, eventID, memberID string) error
+	// Collaborations returns the events userID collaborates on, with roles.
+	Collaborations(ctx context.Context, userID string) ([]models.EventCollaborator, error)
+}
+
+type collaboratorService struct {
+	evtRepo  repositories.EventRepository
+	repo     repositories.CollaboratorRepository
+	userRepo repositories.UserRepository
+	notifier notify.Notifier
+}
+
+func NewCollaboratorService(e repositories.EventRepository, c repositories.CollaboratorRepository, u repositories.UserRepository, n notify.Notifier) CollaboratorService {
+	return &collaboratorService{evtRepo: e, repo: c, userRepo: u, notifier: n}
+}
+
+func (s *collaboratorService) Invite(ctx context.Context, userID, eventID string, req *models.InviteCollaboratorRequest) (*models.EventCollaborator, error) {
+	ev, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit)
+	if err != nil {
+		return nil, err
+	}
+	invitee, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInviteeNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if invitee.ID == ev.OrganizerID {
+		return nil, ErrInviteOrganizer
+	}
+
+	c, err := s.repo.Find(ctx, eventID, invitee.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c = &models.EventCollaborator{EventID: eventID, UserID: invitee.ID, Role: req.Role, InvitedBy: userID}
+		if err := s.repo.Create(ctx, c); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("collaboratorSvc.Invite lookup: %w", err)
+	case c.Role == req.Role:
+		c.User = invitee
+		return c, nil
+	default:
+		c.Role, c.InvitedBy = req.Role, userID
+		if err := s.repo.UpdateRole(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	c.User = invitee
+
+	s.notifier.Notify(context.WithoutCancel(ctx), notify.CollaboratorInvited{
+		EventID: ev.ID, Title: ev.Title, UserID: invitee.ID, Email: invitee.Email,
+		Role: string(c.Role), InvitedBy: userID,
+	})
+	return c, nil
+}
+
+func (s *collaboratorService) List(ctx context.Context, userID, eventID string) ([]models.EventCollaborator, error) {
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessView); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByEvent(ctx, eventID)
+}
+
+func (s *collaboratorService) Remove(ctx context.Context, userID, eventID, memberID string) error {
+	need := accessEdit
+	if memberID == userID {
+		need = accessView
+	}
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, need); err != nil {
+		return err
+	}
+	removed, err := s.repo.Delete(ctx, eventID, memberID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+func (s *collaboratorService) Collaborations(ctx context.Context, userID string) ([]models.EventCollaborator, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+// eventAccess is what a user may do with an event. Each level includes the
+// ones below it.
+type eventAccess int
+
+const (
+	accessNone eventAccess = iota
+	// accessView: the event's stats and collaborators (viewer).
+	accessView
+	// accessCheckIn: check tickets in and see the attendee list (staff).
+	accessCheckIn
+	// accessEdit: edit, publish, cancel, images, refunds, no-shows, clones,
+	// templates and collaborators (co-organizer).
+	accessEdit
+	// accessOwn: delete the event (the organizer).
+	accessOwn
+)
+
+// roleAccess maps each collaborator role to its level.
+var roleAccess = map[models.CollaboratorRole]eventAccess{
+	models.RoleViewer:      accessView,
+	models.RoleStaff:       accessCheckIn,
+	models.RoleCoOrganizer: accessEdit,
+}
+
+// accessTo is the one place that decides what userID may do with ev: the
+// organizer who created it owns it, collaborators get their role's level.
+// ev must have its Collaborators loaded (EventRepository.FindByID does).
+func accessTo(ev *models.Event, userID string) eventAccess {
+	if ev.OrganizerID == userID {
+		return accessOwn
+	}
+	for _, c := range ev.Collaborators {
+		if c.UserID == userID {
+			return roleAccess[c.Role]
+		}
+	}
+	return accessNone
+}
+
+// managedEvent loads eventID for userID, returning ErrEventNotFound when it
+// doesn't exist and ErrNotEventOrganizer when userID's access is below need.
+func managedEvent(ctx context.Context, repo repositories.EventRepository, userID, eventID string, need eventAccess) (*models.Event, error) {
+	ev, err := repo.FindByID(ctx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEventNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if accessTo(ev, userID) < need {
+		return nil, ErrNotEventOrganizer
+	}
+	return ev, nil
+}
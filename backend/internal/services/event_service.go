@@ -1,58 +1,633 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"log"
 	"time"
 
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
 	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
 	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
 )
 
+var (
+	ErrCapacityBelowRegistered = errors.New("capacity cannot be lower than the seats already taken")
+	ErrEventHasRegistrations   = errors.New("event still has active registrations")
+	ErrInvalidEventTransition  = errors.New("event cannot move to that status")
+	// ErrInvalidEvent matches every error that rejects the event's fields as
+	// given; the error's own message says which field and why.
+	ErrInvalidEvent = errors.New("invalid event")
+)
+
+// invalidEvent is a field validation error: it reads as its message but
+// matches ErrInvalidEvent, as well as anything its message wraps.
+type invalidEvent struct{ err error }
+
+func (e invalidEvent) Error() string      { return e.err.Error() }
+func (e invalidEvent) Unwrap() error      { return e.err }
+func (invalidEvent) Is(target error) bool { return target == ErrInvalidEvent }
+
+// invalidf formats a validation error, like fmt.Errorf.
+func invalidf(format string, a ...any) error { return invalidEvent{fmt.Errorf(format, a...)} }
+
 type EventService interface {
-	CreateEvent(req *models.CreateEventRequest, organizerID string) (*models.EventResponse, error)
-	GetEvent(id string) (*models.EventResponse, error)
-	ListEvents() ([]models.EventResponse, error)
+	// CreateEvent creates an event owned by organizerID, first filling in
+	// what req leaves out from req.TemplateID.
+	CreateEvent(ctx context.Context, req *models.CreateEventRequest, organizerID string) (*models.EventResponse, error)
+	// CloneEvent copies eventID to a new start date as a new event owned by
+	// userID. Registrations, check-ins and images are never copied.
+	CloneEvent(ctx context.Context, userID, eventID string, req *models.CloneEventRequest) (*models.EventResponse, error)
+	GetEvent(ctx context.Context, id string) (*models.EventResponse, error)
+	// ListEvents returns the page of published and cancelled events that q
+	// selects; a zero q.Limit means models.DefaultEventPageSize.
+	ListEvents(ctx context.Context, q *models.EventListQuery) (*models.EventPage, error)
+	// SearchEvents full-text searches event titles and descriptions; a zero
+	// q.Limit means models.DefaultEventPageSize.
+	SearchEvents(ctx context.Context, q *models.EventSearchQuery) (*models.EventSearchPage, error)
+	// UpdateEvent applies req to eventID for its organizer or a
+	// co-organizer. It takes the same per-event lock as bookings, so capacity
+	// never drops below a seat count that is still changing; raising it
+	// promotes the waitlist.
+	UpdateEvent(ctx context.Context, userID, eventID string, req *models.UpdateEventRequest) (*models.EventResponse, error)
+	// DeleteEvent removes eventID for its organizer only. Events with active
+	// registrations can't be deleted (ErrEventHasRegistrations).
+	DeleteEvent(ctx context.Context, userID, eventID string) error
+	// PublishEvent opens a draft event to the list and to bookings.
+	PublishEvent(ctx context.Context, userID, eventID string) (*models.EventResponse, error)
+	// CancelEvent cancels eventID and, in the same transaction, every
+	// registration that can still be cancelled, recording reason on each. It
+	// reports how many registrations were cancelled and publishes a
+	// notify.EventCancelled once committed.
+	CancelEvent(ctx context.Context, userID, eventID, reason string) (*models.EventResponse, int, error)
+}
+
+type eventService struct {
+	seatLedger
+	db       *gorm.DB
+	taxRepo   repositories.TaxonomyRepository
+	venueRepo repositories.VenueRepository
+	tplRepo   repositories.TemplateRepository
+	notifier  notify.Notifier
 }
 
-type eventService struct{ eventRepo repositories.EventRepository }
+func NewEventService(db *gorm.DB, e repositories.EventRepository, r repositories.RegistrationRepository,
+	t repositories.TaxonomyRepository, v repositories.VenueRepository, tpl repositories.TemplateRepository,
+	l locking.Locker, n notify.Notifier) EventService {
+	return &eventService{
+		seatLedger: seatLedger{regRepo: r, evtRepo: e, locker: l},
+		db: db, taxRepo: t, venueRepo: v, tplRepo: tpl, notifier: n,
+	}
+}
 
-func NewEventService(r repositories.EventRepository) EventService { return &eventService{eventRepo: r} }
+func (s *eventService) CreateEvent(ctx context.Context, req *models.CreateEventRequest, organizerID string) (*models.EventResponse, error) {
+	if req.TemplateID != "" {
+		tpl, err := ownTemplate(ctx, s.tplRepo, organizerID, req.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		req = prefill(req, tpl)
+	}
+	if req.Title == "" {
+		return nil, invalidf("title is required unless the template gives one")
+	}
+	var venueID *string
+	capacity, zone := req.Capacity, req.Timezone
+	if req.VenueID != "" {
+		venue, err := findVenue(ctx, s.venueRepo, req.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		venueID = &venue.ID
+		if capacity == 0 {
+			capacity = venue.DefaultCapacity
+		}
+		if zone == "" {
+			zone = venue.Timezone
+		}
+	}
+	if capacity == 0 {
+		return nil, invalidf("capacity is required unless the venue has a default capacity")
+	}
+	if zone == "" {
+		zone = "UTC"
+	}
+	loc, err := loadZone(zone)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *eventService) CreateEvent(req *models.CreateEventRequest, organizerID string) (*models.EventResponse, error) {
-	date, err := time.Parse(time.RFC3339, req.EventDate)
+	date, err := parseEventTime(req.EventDate, loc)
 	if err != nil {
-		return nil, fmt.Errorf("invalid event_date (use RFC3339 e.g. 2025-12-31T18:00:00Z): %w", err)
+		return nil, invalidf("invalid event_date: %w", err)
+	}
+	end := date.Add(models.DefaultEventDuration)
+	if req.EndsAt != "" {
+		if end, err = parseEventTime(req.EndsAt, loc); err != nil {
+			return nil, invalidf("invalid ends_at: %w", err)
+		}
+	}
+	if !date.Before(end) {
+		return nil, ErrEndBeforeStart
+	}
+	salesOpen, err := parseOptionalTime(req.SalesOpenAt, loc)
+	if err != nil {
+		return nil, invalidf("invalid sales_open_at: %w", err)
+	}
+	salesClose, err := parseOptionalTime(req.SalesCloseAt, loc)
+	if err != nil {
+		return nil, invalidf("invalid sales_close_at: %w", err)
+	}
+	if err := checkSalesWindow(date, salesOpen, salesClose); err != nil {
+		return nil, err
+	}
+	tiers, err := buildTiers(req.Tiers, capacity, loc)
+	if err != nil {
+		return nil, err
+	}
+	cats, err := resolveCategories(ctx, s.taxRepo, req.Categories)
+	if err != nil {
+		return nil, err
+	}
+	status := models.EventPublished
+	if req.Status != "" {
+		status = models.EventStatus(req.Status)
 	}
 	ev := &models.Event{
 		Title: req.Title, Description: req.Description,
-		Capacity: req.Capacity, EventDate: date, OrganizerID: organizerID,
+		Capacity: capacity, EventDate: date, EndsAt: &end, Timezone: zone, OrganizerID: organizerID,
+		VenueID: venueID, Online: req.Online || req.OnlineURL != "", OnlineURL: req.OnlineURL,
+		SalesOpenAt: salesOpen, SalesCloseAt: salesClose,
+		Status: status, Tiers: tiers,
+		Categories: cats, Tags: normalizeTags(req.Tags),
 	}
-	if err := s.eventRepo.Create(ev); err != nil {
+	if err := s.evtRepo.Create(ctx, ev); err != nil {
 		return nil, err
 	}
 	return toEventResponse(ev), nil
 }
 
-func (s *eventService) GetEvent(id string) (*models.EventResponse, error) {
-	ev, err := s.eventRepo.FindByID(id)
+// prefill returns a copy of req with its empty title, description, capacity
+// and tiers taken from tpl.
+func prefill(req *models.CreateEventRequest, tpl *models.EventTemplate) *models.CreateEventRequest {
+	out := *req
+	if out.Title == "" {
+		out.Title = tpl.Title
+	}
+	if out.Description == "" {
+		out.Description = tpl.Description
+	}
+	if out.Capacity == 0 {
+		out.Capacity = tpl.Capacity
+	}
+	if len(out.Tiers) == 0 {
+		for _, t := range tpl.Tiers {
+			out.Tiers = append(out.Tiers, models.TierRequest{Name: t.Name, Description: t.Description, Capacity: t.Capacity})
+		}
+	}
+	return &out
+}
+
+func (s *eventService) CloneEvent(ctx context.Context, userID, eventID string, req *models.CloneEventRequest) (*models.EventResponse, error) {
+	src, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := loadZone(src.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseEventTime(req.EventDate, loc)
+	if err != nil {
+		return nil, invalidf("invalid event_date: %w", err)
+	}
+	// Every other time moves with the start. They're passed on in UTC, which
+	// parseEventTime takes as given whatever the timezone.
+	shift := date.Sub(src.EventDate)
+	moved := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Add(shift).UTC().Format(time.RFC3339)
+	}
+	end := src.End()
+
+	c := &models.CreateEventRequest{
+		Title: src.Title, Description: src.Description, Capacity: src.Capacity,
+		EventDate: date.UTC().Format(time.RFC3339), EndsAt: moved(&end), Timezone: src.Timezone,
+		SalesOpenAt: moved(src.SalesOpenAt), SalesCloseAt: moved(src.SalesCloseAt),
+		Status: req.Status, Online: src.Online, OnlineURL: src.OnlineURL,
+	}
+	if req.Title != "" {
+		c.Title = req.Title
+	}
+	if src.VenueID != nil {
+		c.VenueID = *src.VenueID
+	}
+	for _, t := range src.Tiers {
+		c.Tiers = append(c.Tiers, models.TierRequest{
+			Name: t.Name, Description: t.Description, Capacity: t.Capacity,
+			SalesStartAt: moved(t.SalesStartAt), SalesEndAt: moved(t.SalesEndAt),
+		})
+	}
+	for _, cat := range src.Categories {
+		c.Categories = append(c.Categories, cat.Slug)
+	}
+	for _, tag := range src.Tags {
+		c.Tags = append(c.Tags, tag.Name)
+	}
+	return s.CreateEvent(ctx, c, userID)
+}
+
+func (s *eventService) GetEvent(ctx context.Context, id string) (*models.EventResponse, error) {
+	ev, err := s.evtRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return toEventResponse(ev), nil
 }
 
-func (s *eventService) ListEvents() ([]models.EventResponse, error) {
-	evs, err := s.eventRepo.List()
+func (s *eventService) ListEvents(ctx context.Context, q *models.EventListQuery) (*models.EventPage, error) {
+	if q.Near != "" {
+		origin, err := parseNear(q.Near)
+		if err != nil {
+			return nil, err
+		}
+		q.Origin = origin
+		if q.Radius <= 0 {
+			q.Radius = models.DefaultRadiusKm
+		}
+	} else if q.Sort == "distance" {
+		return nil, fmt.Errorf("%w: sort=distance needs near", ErrInvalidNear)
+	}
+	if q.Limit <= 0 {
+		q.Limit = models.DefaultEventPageSize
+	}
+	if q.Limit > models.MaxEventPageSize {
+		q.Limit = models.MaxEventPageSize
+	}
+	evs, total, err := s.evtRepo.List(ctx, q)
 	if err != nil {
 		return nil, err
 	}
-	resp := make([]models.EventResponse, len(evs))
+	page := &models.EventPage{
+		Events: make([]models.EventResponse, len(evs)),
+		Count:  len(evs),
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
 	for i := range evs {
-		resp[i] = *toEventResponse(&evs[i])
+		resp := toEventResponse(&evs[i])
+		if q.Origin != nil && evs[i].Venue != nil {
+			d := math.Round(q.Origin.DistanceKm(evs[i].Venue.Point())*10) / 10
+			resp.DistanceKm = &d
+		}
+		page.Events[i] = *resp
 	}
-	return resp, nil
+	return page, nil
+}
+
+func (s *eventService) SearchEvents(ctx context.Context, q *models.EventSearchQuery) (*models.EventSearchPage, error) {
+	if q.Limit <= 0 {
+		q.Limit = models.DefaultEventPageSize
+	}
+	matches, total, err := s.evtRepo.Search(ctx, q.Q, q.Limit, q.Offset)
+	if err != nil {
+		return nil, err
+	}
+	page := &models.EventSearchPage{
+		Query:   q.Q,
+		Results: make([]models.EventSearchResult, len(matches)),
+		Count:   len(matches),
+		Total:   total,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+	}
+	for i := range matches {
+		m := &matches[i]
+		page.Results[i] = models.EventSearchResult{
+			EventResponse: toEventResponse(&m.Event),
+			Rank:          m.Rank,
+			Highlight:     models.EventHighlight{Title: m.Title, Description: m.Snippet},
+		}
+	}
+	return page, nil
+}
+
+func (s *eventService) UpdateEvent(ctx context.Context, userID, eventID string, req *models.UpdateEventRequest) (resp *models.EventResponse, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit); err != nil {
+		return nil, err
+	}
+	unlock, err := s.lock(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ev, err := s.evtRepo.FindByID(ctx, eventID) // re-read under the lock
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == models.EventCancelled {
+		return nil, ErrEventCancelled
+	}
+	oldCapacity := ev.Capacity
+	if err := applyEventUpdate(ev, req); err != nil {
+		return nil, err
+	}
+
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := s.evtRepo.Update(tx, ev)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCapacityBelowRegistered
+		}
+		if ev.Capacity <= oldCapacity {
+			return nil
+		}
+		// New seats go to the waitlist first: the untiered queue, then
+		// each tier's.
+		if err := s.promoteWaitlist(tx, eventID, nil); err != nil {
+			return err
+		}
+		for i := range ev.Tiers {
+			if err := s.promoteWaitlist(tx, eventID, &ev.Tiers[i].ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	log.Printf("EVENT UPDATED | organizer=%s event=%s capacity=%d", userID, eventID, ev.Capacity)
+	return s.GetEvent(ctx, eventID)
+}
+
+func (s *eventService) DeleteEvent(ctx context.Context, userID, eventID string) (err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessOwn); err != nil {
+		return err
+	}
+	unlock, err := s.lock(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		n, err := s.regRepo.CountActive(tx, eventID)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w (%d)", ErrEventHasRegistrations, n)
+		}
+		return s.evtRepo.Delete(tx, eventID)
+	})
+	if txErr != nil {
+		return txErr
+	}
+	log.Printf("EVENT DELETED | organizer=%s event=%s", userID, eventID)
+	return nil
+}
+
+func (s *eventService) PublishEvent(ctx context.Context, userID, eventID string) (*models.EventResponse, error) {
+	ev, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit)
+	if err != nil {
+		return nil, err
+	}
+	if !ev.Status.CanTransitionTo(models.EventPublished) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidEventTransition, ev.Status, models.EventPublished)
+	}
+	ok, err := s.evtRepo.UpdateStatus(s.db.WithContext(ctx), eventID, ev.Status, models.EventPublished, "")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidEventTransition // changed by a concurrent request
+	}
+	log.Printf("EVENT PUBLISHED | organizer=%s event=%s", userID, eventID)
+	return s.GetEvent(ctx, eventID)
+}
+
+// CancelEvent runs under the per-event lock, so no booking can slip in
+// between the event flipping to cancelled and its registrations following.
+// Checked-in and no-show registrations are history and are left alone.
+func (s *eventService) CancelEvent(ctx context.Context, userID, eventID, reason string) (resp *models.EventResponse, n int, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit); err != nil {
+		return nil, 0, err
+	}
+	unlock, err := s.lock(ctx, eventID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer unlock()
+
+	ev, err := s.evtRepo.FindByID(ctx, eventID) // re-read under the lock
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ev.Status.CanTransitionTo(models.EventCancelled) {
+		return nil, 0, fmt.Errorf("%w: %s to %s", ErrInvalidEventTransition, ev.Status, models.EventCancelled)
+	}
+
+	msg := notify.EventCancelled{EventID: ev.ID, Title: ev.Title, Reason: reason, CancelledBy: userID}
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := s.evtRepo.UpdateStatus(tx, eventID, ev.Status, models.EventCancelled, reason)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidEventTransition
+		}
+		regs, err := s.regRepo.ListActive(tx, eventID)
+		if err != nil {
+			return err
+		}
+		for i := range regs {
+			reg := &regs[i]
+			if !reg.Status.CanTransitionTo(models.StatusCancelled) {
+				continue
+			}
+			heldSeat := reg.Status.HoldsSeat()
+			ok, err := transition(tx, s.regRepo, reg, models.StatusCancelled, userID, reason)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if heldSeat {
+				if err := s.releaseSeats(tx, eventID, reg.TierID, reg.Quantity); err != nil {
+					return err
+				}
+			}
+			msg.Registrations = append(msg.Registrations, notify.CancelledRegistration{RegistrationID: reg.ID, UserID: reg.UserID})
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, 0, txErr
+	}
+	log.Printf("EVENT CANCELLED | organizer=%s event=%s registrations=%d", userID, eventID, len(msg.Registrations))
+
+	resp, err = s.GetEvent(ctx, eventID)
+	msg.CancelledAt = time.Now()
+	if err == nil && resp.CancelledAt != nil {
+		msg.CancelledAt = *resp.CancelledAt
+	}
+	// The cancellation has committed; tell the notifier even if this request
+	// is on its way out.
+	s.notifier.Notify(context.WithoutCancel(ctx), msg)
+	return resp, len(msg.Registrations), err
+}
+
+// applyEventUpdate copies the fields set in req onto ev and validates the
+// result the way CreateEvent validates a new event.
+func applyEventUpdate(ev *models.Event, req *models.UpdateEventRequest) error {
+	if req.Title != nil {
+		ev.Title = *req.Title
+	}
+	if req.Description != nil {
+		ev.Description = *req.Description
+	}
+	if req.Timezone != nil {
+		if _, err := loadZone(*req.Timezone); err != nil {
+			return err
+		}
+		ev.Timezone = *req.Timezone
+	}
+	loc, err := loadZone(ev.Timezone)
+	if err != nil {
+		return err
+	}
+	if req.EventDate != nil {
+		date, err := parseEventTime(*req.EventDate, loc)
+		if err != nil {
+			return invalidf("invalid event_date: %w", err)
+		}
+		ev.EventDate = date
+	}
+	if req.EndsAt != nil {
+		end, err := parseEventTime(*req.EndsAt, loc)
+		if err != nil {
+			return invalidf("invalid ends_at: %w", err)
+		}
+		ev.EndsAt = &end
+	}
+	if !ev.EventDate.Before(ev.End()) {
+		return ErrEndBeforeStart
+	}
+	if req.SalesOpenAt != nil {
+		t, err := parseOptionalTime(*req.SalesOpenAt, loc)
+		if err != nil {
+			return invalidf("invalid sales_open_at: %w", err)
+		}
+		ev.SalesOpenAt = t
+	}
+	if req.SalesCloseAt != nil {
+		t, err := parseOptionalTime(*req.SalesCloseAt, loc)
+		if err != nil {
+			return invalidf("invalid sales_close_at: %w", err)
+		}
+		ev.SalesCloseAt = t
+	}
+	if err := checkSalesWindow(ev.EventDate, ev.SalesOpenAt, ev.SalesCloseAt); err != nil {
+		return err
+	}
+	if req.Capacity != nil {
+		if *req.Capacity < ev.Registered {
+			return fmt.Errorf("%w: %d seats are taken", ErrCapacityBelowRegistered, ev.Registered)
+		}
+		for _, t := range ev.Tiers {
+			if t.Capacity > *req.Capacity {
+				return invalidf("tier %q capacity %d exceeds event capacity %d", t.Name, t.Capacity, *req.Capacity)
+			}
+		}
+		ev.Capacity = *req.Capacity
+	}
+	return nil
+}
+
+// checkSalesWindow requires the sales window to open before it closes; sales
+// close at the event date unless salesClose is set.
+func checkSalesWindow(date time.Time, salesOpen, salesClose *time.Time) error {
+	closeAt := date
+	if salesClose != nil {
+		closeAt = *salesClose
+	}
+	if salesOpen != nil && !salesOpen.Before(closeAt) {
+		return invalidf("sales_open_at must be before sales close (%s)", closeAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// buildTiers validates tier requests against the event capacity. Each tier
+// may be at most the event's size; the event capacity caps their total sales.
+func buildTiers(reqs []models.TierRequest, capacity int, loc *time.Location) ([]models.TicketTier, error) {
+	tiers := make([]models.TicketTier, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for _, r := range reqs {
+		if seen[r.Name] {
+			return nil, invalidf("duplicate tier name %q", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Capacity > capacity {
+			return nil, invalidf("tier %q capacity %d exceeds event capacity %d", r.Name, r.Capacity, capacity)
+		}
+		start, err := parseOptionalTime(r.SalesStartAt, loc)
+		if err != nil {
+			return nil, invalidf("tier %q: invalid sales_start_at: %w", r.Name, err)
+		}
+		end, err := parseOptionalTime(r.SalesEndAt, loc)
+		if err != nil {
+			return nil, invalidf("tier %q: invalid sales_end_at: %w", r.Name, err)
+		}
+		if start != nil && end != nil && !start.Before(*end) {
+			return nil, invalidf("tier %q: sales_start_at must be before sales_end_at", r.Name)
+		}
+		tiers = append(tiers, models.TicketTier{
+			Name: r.Name, Description: r.Description, Capacity: r.Capacity,
+			SalesStartAt: start, SalesEndAt: end,
+		})
+	}
+	return tiers, nil
 }
 
 func toEventResponse(e *models.Event) *models.EventResponse {
-	return &models.EventResponse{Event: e, AvailableSeats: e.AvailableSeats()}
+	now := time.Now()
+	for i := range e.Tiers {
+		t := &e.Tiers[i]
+		t.AvailableSeats = t.Capacity - t.Registered
+		t.OnSale = t.OnSaleAt(now)
+	}
+	resp := &models.EventResponse{
+		Event: e, AvailableSeats: e.AvailableSeats(), SalesOpen: e.OnSaleAt(now), Phase: e.PhaseAt(now),
+	}
+	loc, err := loadZone(e.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	resp.LocalStart = e.EventDate.In(loc).Format(time.RFC3339)
+	resp.LocalEnd = e.End().In(loc).Format(time.RFC3339)
+	for i := range e.Media {
+		m := mediaResponse(&e.Media[i])
+		if m.Kind == models.MediaCover {
+			resp.Cover = m
+		} else {
+			resp.Gallery = append(resp.Gallery, *m)
+		}
+	}
+	return resp
 }
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEndBeforeStart  = errors.New("ends_at must be after event_date")
+	ErrNonexistentTime = errors.New("local time does not exist in the event's timezone (skipped by a DST change)")
+	ErrAmbiguousTime   = errors.New("local time occurs twice in the event's timezone (DST change); give an explicit offset")
+	ErrOffsetMismatch  = errors.New("UTC offset does not match the event's timezone at that time")
+)
+
+// localLayouts are the wall-clock forms accepted for event times, read in the
+// event's timezone.
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// parseEventTime reads v in the event's timezone loc. v is either RFC3339 or
+// a local wall-clock time ("2026-10-25T02:30"). RFC3339 times in UTC ("Z")
+// are taken as given; any other offset must be loc's offset at that instant,
+// which catches a winter offset on a summer date. Local times that a DST
+// change skips or repeats are rejected rather than silently shifted.
+func parseEventTime(v string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		_, got := t.Zone()
+		if _, want := t.In(loc).Zone(); got != 0 && got != want {
+			return time.Time{}, fmt.Errorf("%w: %s is %s in %s", ErrOffsetMismatch, v, t.In(loc).Format(time.RFC3339), loc)
+		}
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		wall, err := time.Parse(layout, v)
+		if err != nil {
+			continue
+		}
+		return resolveLocal(wall, loc)
+	}
+	return time.Time{}, fmt.Errorf("%q is neither RFC3339 (2026-12-31T18:00:00Z) nor a local time (2026-12-31T18:00)", v)
+}
+
+// resolveLocal places wall's clock reading in loc.
+func resolveLocal(wall time.Time, loc *time.Location) (time.Time, error) {
+	const clock = "2006-01-02T15:04:05"
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	if t.Format(clock) != wall.Format(clock) {
+		// time.Date moved a time in a spring-forward gap past the gap.
+		return time.Time{}, fmt.Errorf("%w: %s in %s", ErrNonexistentTime, wall.Format(clock), loc)
+	}
+	// In a fall-back overlap the same reading is also shown one DST shift
+	// earlier or later.
+	_, before := t.Add(-12 * time.Hour).Zone()
+	_, after := t.Add(12 * time.Hour).Zone()
+	if shift := time.Duration(before-after) * time.Second; shift != 0 {
+		for _, u := range []time.Time{t.Add(shift), t.Add(-shift)} {
+			if u.In(loc).Format(clock) == t.Format(clock) {
+				return time.Time{}, fmt.Errorf("%w: %s in %s", ErrAmbiguousTime, wall.Format(clock), loc)
+			}
+		}
+	}
+	return t, nil
+}
+
+// parseOptionalTime is parseEventTime treating "" as unset.
+func parseOptionalTime(v string, loc *time.Location) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := parseEventTime(v, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+var zones sync.Map // IANA name → *time.Location
+
+// loadZone resolves an IANA timezone name, caching the result. "Local" is
+// refused: it would mean whatever zone the server happens to run in.
+func loadZone(name string) (*time.Location, error) {
+	if loc, ok := zones.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+	zones.Store(name, loc)
+	return loc, nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+var ErrInvalidTransition = errors.New("registration cannot move to that status")
+
+// transition is the single path by which a registration changes status. It
+// rejects moves the lifecycle (models.RegistrationStatus.CanTransitionTo) does
+// not allow, then applies the conditional update and records the change with
+// its actor inside tx. actorID "" means the system did it. The bool is false
+// when a concurrent request changed reg first; reg.Status is updated on
+// success.
+func transition(tx *gorm.DB, repo repositories.RegistrationRepository, reg *models.Registration, to models.RegistrationStatus, actorID, reason string) (bool, error) {
+	if !reg.Status.CanTransitionTo(to) {
+		return false, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, reg.Status, to)
+	}
+	change := &models.RegistrationStatusChange{
+		RegistrationID: reg.ID, FromStatus: reg.Status, ToStatus: to, Reason: reason,
+	}
+	if actorID != "" {
+		change.ActorID = &actorID
+	}
+	ok, err := repo.UpdateStatus(tx, change)
+	if err != nil || !ok {
+		return false, err
+	}
+	reg.Status = to
+	return true, nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/imaging"
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/storage"
+)
+
+var (
+	ErrMediaNotFound    = errors.New("media not found")
+	ErrMediaTooLarge    = errors.New("file too large")
+	ErrUnsupportedMedia = errors.New("unsupported media")
+	ErrGalleryFull      = fmt.Errorf("gallery is full (%d images)", models.MaxGalleryImages)
+)
+
+// MediaService manages event images. Uploads are checked by content, not by
+// the client's Content-Type, and stored with medium and thumbnail copies.
+type MediaService interface {
+	// Upload stores the image read from r as eventID's cover (replacing any
+	// previous one) or as a gallery image. Organizer or co-organizer only.
+	Upload(ctx context.Context, userID, eventID string, kind models.MediaKind, r io.Reader) (*models.MediaResponse, error)
+	// Delete removes one of eventID's images. Organizer or co-organizer
+	// only.
+	Delete(ctx context.Context, userID, eventID, mediaID string) error
+	// Open returns a variant of an image and its content type.
+	Open(ctx context.Context, eventID, mediaID, variant string) (io.ReadCloser, string, error)
+	// MaxBytes is the largest accepted upload.
+	MaxBytes() int64
+}
+
+type mediaService struct {
+	db       *gorm.DB
+	evtRepo  repositories.EventRepository
+	repo     repositories.MediaRepository
+	blobs    storage.BlobStore
+	locker   locking.Locker // per event; keeps two concurrent cover uploads from both keeping theirs
+	maxBytes int64
+}
+
+// NewMediaService reads MEDIA_MAX_BYTES (default 5 MiB) for the upload size
+// limit.
+func NewMediaService(db *gorm.DB, e repositories.EventRepository, m repositories.MediaRepository, b storage.BlobStore, l locking.Locker) MediaService {
+	maxBytes := int64(5 << 20)
+	if v := os.Getenv("MEDIA_MAX_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			maxBytes = n
+		} else {
+			log.Printf("ignoring invalid MEDIA_MAX_BYTES=%q", v)
+		}
+	}
+	return &mediaService{db: db, evtRepo: e, repo: m, blobs: b, locker: l, maxBytes: maxBytes}
+}
+
+func (s *mediaService) MaxBytes() int64 { return s.maxBytes }
+
+func (s *mediaService) Upload(ctx context.Context, userID, eventID string, kind models.MediaKind, r io.Reader) (resp *models.MediaResponse, err error) {
+	defer func() { err = ctxErr(ctx, err) }()
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("mediaSvc.Upload read: %w", err)
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrMediaTooLarge, s.maxBytes)
+	}
+	img, err := imaging.Decode(data)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return nil, fmt.Errorf("%w: %v", ErrMediaTooLarge, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedMedia, err)
+	}
+
+	m := &models.EventMedia{
+		ID: uuid.New().String(), EventID: eventID, Kind: kind,
+		ContentType: img.ContentType, Size: int64(len(data)), Width: img.Width, Height: img.Height,
+	}
+	prefix := "events/" + eventID + "/" + m.ID + "/"
+	m.OriginalKey = prefix + "original" + imaging.Ext(img.ContentType)
+	files := map[string][]byte{m.OriginalKey: data}
+	for _, v := range []struct {
+		key  *string
+		name string
+		side int
+	}{{&m.MediumKey, "medium", models.MediumImageSide}, {&m.ThumbKey, "thumb", models.ThumbImageSide}} {
+		out, ct, ok, err := img.Fit(v.side)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			*v.key = prefix + v.name + imaging.Ext(ct)
+			m.VariantType = ct
+			files[*v.key] = out
+		}
+	}
+
+	// Files first, then the row: a failed insert leaves orphan files to
+	// clean up, never a row pointing at nothing.
+	for key, b := range files {
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(b)); err != nil {
+			s.remove(m)
+			return nil, err
+		}
+	}
+
+	unlock, err := lockEvent(ctx, s.locker, eventID)
+	if err != nil {
+		s.remove(m)
+		return nil, err
+	}
+	defer unlock()
+	var replaced *models.EventMedia
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if kind == models.MediaCover {
+			old, err := s.repo.FindCover(tx, eventID)
+			if err == nil {
+				replaced = old
+				if err := s.repo.Delete(tx, old.ID); err != nil {
+					return err
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		} else {
+			n, err := s.repo.CountGallery(tx, eventID)
+			if err != nil {
+				return err
+			}
+			if n >= models.MaxGalleryImages {
+				return ErrGalleryFull
+			}
+		}
+		return s.repo.Create(tx, m)
+	})
+	if txErr != nil {
+		s.remove(m)
+		return nil, txErr
+	}
+	if replaced != nil {
+		s.remove(replaced)
+	}
+	return mediaResponse(m), nil
+}
+
+func (s *mediaService) Delete(ctx context.Context, userID, eventID, mediaID string) error {
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessEdit); err != nil {
+		return err
+	}
+	m, err := s.repo.FindByID(ctx, eventID, mediaID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMediaNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(s.db.WithContext(ctx), m.ID); err != nil {
+		return err
+	}
+	s.remove(m)
+	return nil
+}
+
+func (s *mediaService) Open(ctx context.Context, eventID, mediaID, variant string) (io.ReadCloser, string, error) {
+	m, err := s.repo.FindByID(ctx, eventID, mediaID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrMediaNotFound
+	} else if err != nil {
+		return nil, "", err
+	}
+	key, ct := m.OriginalKey, m.ContentType
+	switch variant {
+	case "original":
+	case "medium", "thumb":
+		// Small originals have no copies; the original stands in.
+		if k := map[string]string{"medium": m.MediumKey, "thumb": m.ThumbKey}[variant]; k != "" {
+			key, ct = k, m.VariantType
+		}
+	default:
+		return nil, "", ErrMediaNotFound
+	}
+	rc, err := s.blobs.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, "", ErrMediaNotFound
+	}
+	return rc, ct, err
+}
+
+// remove deletes m's files, best effort: a leftover file only wastes space.
+func (s *mediaService) remove(m *models.EventMedia) {
+	for _, key := range m.Keys() {
+		if err := s.blobs.Delete(context.Background(), key); err != nil {
+			log.Printf("MEDIA CLEANUP FAILED | key=%s err=%v", key, err)
+		}
+	}
+}
+
+// mediaResponse adds the URLs of m's variants, served by
+// GET /api/events/:id/media/:mediaId/:variant.
+func mediaResponse(m *models.EventMedia) *models.MediaResponse {
+	base := fmt.Sprintf("/api/events/%s/media/%s/", m.EventID, m.ID)
+	return &models.MediaResponse{
+		EventMedia: m, URL: base + "original", MediumURL: base + "medium", ThumbnailURL: base + "thumb",
+	}
+}
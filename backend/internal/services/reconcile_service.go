@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+// SeatReconciler recomputes the denormalised seat counters (Event.Registered
+// and TicketTier.Registered) from the registrations that hold seats, for when
+// a manual edit or a partial failure has left them out of step.
+type SeatReconciler interface {
+	// Reconcile checks every event and reports each counter that drifted.
+	// Unless dryRun is set, drifted counters are overwritten with the
+	// recomputed value.
+	Reconcile(ctx context.Context, dryRun bool) (*models.ReconcileReport, error)
+}
+
+type seatReconciler struct {
+	seatLedger
+	db *gorm.DB
+}
+
+func NewSeatReconciler(db *gorm.DB, r repositories.RegistrationRepository, e repositories.EventRepository, l locking.Locker) SeatReconciler {
+	return &seatReconciler{seatLedger: seatLedger{regRepo: r, evtRepo: e, locker: l}, db: db}
+}
+
+func (s *seatReconciler) Reconcile(ctx context.Context, dryRun bool) (*models.ReconcileReport, error) {
+	ids, err := s.evtRepo.IDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.ReconcileReport{DryRun: dryRun, Drift: []models.SeatDrift{}}
+	for _, id := range ids {
+		drift, err := s.reconcileEvent(ctx, id, dryRun)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue // deleted since we listed it
+		} else if err != nil {
+			return report, err
+		}
+		report.EventsChecked++
+		report.Drift = append(report.Drift, drift...)
+	}
+	return report, nil
+}
+
+// reconcileEvent compares one event's counters with its registrations under
+// the booking lock, so an in-flight booking can't be mistaken for drift, and
+// corrects them in a single transaction.
+func (s *seatReconciler) reconcileEvent(ctx context.Context, eventID string, dryRun bool) ([]models.SeatDrift, error) {
+	unlock, err := s.lock(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ev, err := s.evtRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	total, byTier, err := s.regRepo.SeatCounts(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []models.SeatDrift
+	if ev.Registered != total {
+		drift = append(drift, models.SeatDrift{EventID: ev.ID, Title: ev.Title, Stored: ev.Registered, Actual: total})
+	}
+	for i := range ev.Tiers {
+		t := &ev.Tiers[i]
+		if t.Registered != byTier[t.ID] {
+			drift = append(drift, models.SeatDrift{
+				EventID: ev.ID, Title: ev.Title, TierID: &t.ID, TierName: t.Name,
+				Stored: t.Registered, Actual: byTier[t.ID],
+			})
+		}
+	}
+	for _, d := range drift {
+		log.Printf("SEAT DRIFT | event=%s tier=%s stored=%d actual=%d fixed=%t", d.EventID, d.TierName, d.Stored, d.Actual, !dryRun)
+	}
+	if dryRun || len(drift) == 0 {
+		return drift, nil
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, d := range drift {
+			if d.TierID == nil {
+				err = s.evtRepo.SetRegistered(tx, d.EventID, d.Actual)
+			} else {
+				err = s.evtRepo.SetTierRegistered(tx, *d.TierID, d.Actual)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+// seatLedger holds the seat-counting steps shared by every service that moves
+// seats: the Layer 1 lock and the claim/release/promote sequence that keeps
+// Event.Registered, the tier counters and the waitlist in step.
+type seatLedger struct {
+	regRepo repositories.RegistrationRepository
+	evtRepo repositories.EventRepository
+	locker  locking.Locker // Layer 1, keyed by event ID
+}
+
+// lock takes the Layer 1 lock for eventID.
+func (s seatLedger) lock(ctx context.Context, eventID string) (func(), error) {
+	return lockEvent(ctx, s.locker, eventID)
+}
+
+// lockEvent takes locker's lock for eventID, mapping a lock timeout (or the
+// request deadline passing while waiting) to ErrTimeout.
+func lockEvent(ctx context.Context, locker locking.Locker, eventID string) (func(), error) {
+	unlock, err := locker.Lock(ctx, eventID)
+	if errors.Is(err, locking.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("LOCK TIMEOUT | event=%s", eventID)
+		return nil, ErrTimeout
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock: %w", err)
+	}
+	return unlock, nil
+}
+
+// claimSeats takes n seats from the event and, when tierID is set, from the
+// tier, all or nothing. The event row is always claimed before the tier row.
+func (s seatLedger) claimSeats(tx *gorm.DB, eventID string, tierID *string, n int) error {
+	_, ok, err := s.evtRepo.IncrementRegistered(tx, eventID, n)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrEventFull
+	}
+	if tierID == nil {
+		return nil
+	}
+	ok, err = s.evtRepo.IncrementTierRegistered(tx, *tierID, n)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Give the event seats back; the row locks are still ours.
+		if err := s.evtRepo.DecrementRegistered(tx, eventID, n); err != nil {
+			return err
+		}
+		return ErrTierSoldOut
+	}
+	return nil
+}
+
+// releaseSeats is the inverse of claimSeats.
+func (s seatLedger) releaseSeats(tx *gorm.DB, eventID string, tierID *string, n int) error {
+	if err := s.evtRepo.DecrementRegistered(tx, eventID, n); err != nil {
+		return err
+	}
+	if tierID == nil {
+		return nil
+	}
+	return s.evtRepo.DecrementTierRegistered(tx, *tierID, n)
+}
+
+// promoteWaitlist moves waitlisted registrations into confirmed, oldest first,
+// for as long as claimSeats can fit the head of the queue. Each tier (and the
+// untiered pool) has its own queue; a group that doesn't fit blocks those
+// behind it, keeping the queue strictly FIFO. It must run inside the
+// transaction (and under the per-event lock) that released the seats.
+func (s seatLedger) promoteWaitlist(tx *gorm.DB, eventID string, tierID *string) error {
+	for {
+		next, err := s.regRepo.NextWaitlisted(tx, eventID, tierID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("promoteWaitlist lookup: %w", err)
+		}
+		err = s.claimSeats(tx, eventID, tierID, next.Quantity)
+		if errors.Is(err, ErrEventFull) || errors.Is(err, ErrTierSoldOut) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		ok, err := transition(tx, s.regRepo, next, models.StatusConfirmed, "", "promoted from waitlist")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// Changed by a concurrent request: roll back rather than keep the
+			// seats just claimed and pick the same row again.
+			return ErrRegistrationNotFound
+		}
+		log.Printf("WAITLIST PROMOTED | user=%s event=%s reg=%s", next.UserID, eventID, next.ID)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"math"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+// StatsService serves the organizer dashboard.
+type StatsService interface {
+	// OrganizerStats covers every event organizerID owns or collaborates on,
+	// drafts included.
+	OrganizerStats(ctx context.Context, organizerID string, q *models.StatsQuery) (*models.OrganizerStats, error)
+	// EventStats covers one event, with its own timeline, for a user who may
+	// manage it.
+	EventStats(ctx context.Context, userID, eventID string, q *models.StatsQuery) (*models.EventStats, error)
+}
+
+type statsService struct {
+	evtRepo repositories.EventRepository
+	repo    repositories.StatsRepository
+}
+
+func NewStatsService(e repositories.EventRepository, s repositories.StatsRepository) StatsService {
+	return &statsService{evtRepo: e, repo: s}
+}
+
+func (s *statsService) OrganizerStats(ctx context.Context, organizerID string, q *models.StatsQuery) (*models.OrganizerStats, error) {
+	q.OrganizerID, q.EventID = organizerID, ""
+	if q.Bucket == "" {
+		q.Bucket = "day"
+	}
+	evs, err := s.repo.EventStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	timeline, err := s.repo.Timeline(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &models.OrganizerStats{Events: make([]models.EventStats, 0, len(evs)), Bucket: q.Bucket, Timeline: timeline}
+	if out.Timeline == nil {
+		out.Timeline = []models.StatsBucket{}
+	}
+	t := &out.Totals
+	for i := range evs {
+		ev := &evs[i]
+		derive(ev)
+		out.Events = append(out.Events, *ev)
+		t.Events++
+		t.Capacity += ev.Capacity
+		t.SeatsSold += ev.SeatsSold
+		t.Bookings += ev.Bookings
+		t.Cancellations += ev.Cancellations
+		t.CheckedIn += ev.CheckedIn
+		if ev.SoldOutAt != nil {
+			t.SoldOut++
+		}
+	}
+	t.SellThroughPct = percent(t.SeatsSold, t.Capacity)
+	t.CheckInRatePct = percent(t.CheckedIn, t.SeatsSold)
+	return out, nil
+}
+
+func (s *statsService) EventStats(ctx context.Context, userID, eventID string, q *models.StatsQuery) (*models.EventStats, error) {
+	if _, err := managedEvent(ctx, s.evtRepo, userID, eventID, accessView); err != nil {
+		return nil, err
+	}
+	q.OrganizerID, q.EventID = "", eventID
+	if q.Bucket == "" {
+		q.Bucket = "day"
+	}
+	evs, err := s.repo.EventStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(evs) == 0 {
+		return nil, ErrEventNotFound // deleted since managedEvent
+	}
+	ev := &evs[0]
+	derive(ev)
+	if ev.Timeline, err = s.repo.Timeline(ctx, q); err != nil {
+		return nil, err
+	}
+	if ev.Timeline == nil {
+		ev.Timeline = []models.StatsBucket{}
+	}
+	ev.Bucket = q.Bucket
+	return ev, nil
+}
+
+// derive fills in the figures computed from ev's counts: the percentages
+// and, once every seat is sold, the sell-out time.
+func derive(ev *models.EventStats) {
+	ev.SellThroughPct = percent(ev.SeatsSold, ev.Capacity)
+	ev.CheckInRatePct = percent(ev.CheckedIn, ev.SeatsSold)
+	if ev.Capacity > 0 && ev.SeatsSold >= ev.Capacity && ev.LastSaleAt != nil {
+		ev.SoldOutAt = ev.LastSaleAt
+		secs := int64(math.Max(0, ev.SoldOutAt.Sub(ev.SalesStart).Seconds()))
+		ev.TimeToSellOutSeconds = &secs
+	}
+}
+
+// percent is n as a percentage of of, to one decimal place; 0 when of is 0.
+func percent(n, of int64) float64 {
+	if of <= 0 {
+		return 0
+	}
+	return math.Round(float64(n)*1000/float64(of)) / 10
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+var (
+	ErrCategoryExists  = errors.New("a category with this name or slug already exists")
+	ErrUnknownCategory = errors.New("unknown category")
+)
+
+// popularTagLimit is how many tags GET /api/categories reports.
+const popularTagLimit = 20
+
+type TaxonomyService interface {
+	// CreateCategory adds a category organizers can file events under.
+	CreateCategory(ctx context.Context, organizerID string, req *models.CreateCategoryRequest) (*models.Category, error)
+	// Taxonomy lists every category and the most used tags, with the number
+	// of listed events for each.
+	Taxonomy(ctx context.Context) (*models.Taxonomy, error)
+}
+
+type taxonomyService struct{ repo repositories.TaxonomyRepository }
+
+func NewTaxonomyService(r repositories.TaxonomyRepository) TaxonomyService {
+	return &taxonomyService{repo: r}
+}
+
+func (s *taxonomyService) CreateCategory(ctx context.Context, organizerID string, req *models.CreateCategoryRequest) (*models.Category, error) {
+	slug := slugify(req.Slug)
+	if slug == "" {
+		slug = slugify(req.Name)
+	}
+	if slug == "" {
+		return nil, errors.New("category name needs at least one letter or digit")
+	}
+	name := strings.TrimSpace(req.Name)
+	if _, err := s.repo.FindCategory(ctx, slug, name); err == nil {
+		return nil, ErrCategoryExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("taxonomySvc.CreateCategory lookup: %w", err)
+	}
+	c := &models.Category{Slug: slug, Name: name, Description: req.Description, CreatedBy: organizerID}
+	if err := s.repo.CreateCategory(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *taxonomyService) Taxonomy(ctx context.Context) (*models.Taxonomy, error) {
+	cats, err := s.repo.CategoryCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.repo.PopularTags(ctx, popularTagLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Taxonomy{Categories: cats, PopularTags: tags}, nil
+}
+
+// resolveCategories looks up the categories for slugs, failing with
+// ErrUnknownCategory if any is missing.
+func resolveCategories(ctx context.Context, repo repositories.TaxonomyRepository, slugs []string) ([]models.Category, error) {
+	if len(slugs) == 0 {
+		return nil, nil
+	}
+	cats, err := repo.FindCategoriesBySlug(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(cats))
+	for _, c := range cats {
+		found[c.Slug] = true
+	}
+	for _, slug := range slugs {
+		if !found[slug] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCategory, slug)
+		}
+	}
+	return cats, nil
+}
+
+// normalizeTags lower-cases and trims tags, dropping blanks and duplicates.
+func normalizeTags(names []string) []models.Tag {
+	seen := make(map[string]bool, len(names))
+	var tags []models.Tag
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		tags = append(tags, models.Tag{Name: n})
+	}
+	return tags
+}
+
+// slugify lower-cases s and joins its runs of letters and digits with "-".
+func slugify(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrTemplateExists   = errors.New("you already have a template with this name")
+)
+
+// TemplateService manages organizers' event templates. A template is only
+// ever visible to the organizer who saved it; anyone else gets
+// ErrTemplateNotFound.
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, organizerID string, req *models.CreateTemplateRequest) (*models.EventTemplate, error)
+	GetTemplate(ctx context.Context, organizerID, id string) (*models.EventTemplate, error)
+	ListTemplates(ctx context.Context, organizerID string) ([]models.EventTemplate, error)
+	DeleteTemplate(ctx context.Context, organizerID, id string) error
+}
+
+type templateService struct {
+	repo    repositories.TemplateRepository
+	evtRepo repositories.EventRepository
+}
+
+func NewTemplateService(t repositories.TemplateRepository, e repositories.EventRepository) TemplateService {
+	return &templateService{repo: t, evtRepo: e}
+}
+
+func (s *templateService) CreateTemplate(ctx context.Context, organizerID string, req *models.CreateTemplateRequest) (*models.EventTemplate, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if _, err := s.repo.FindByName(ctx, organizerID, name); err == nil {
+		return nil, ErrTemplateExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("templateSvc.CreateTemplate lookup: %w", err)
+	}
+
+	t := &models.EventTemplate{OrganizerID: organizerID, Name: name}
+	if req.EventID != "" {
+		ev, err := managedEvent(ctx, s.evtRepo, organizerID, req.EventID, accessEdit)
+		if err != nil {
+			return nil, err
+		}
+		t.Title, t.Description, t.Capacity = ev.Title, ev.Description, ev.Capacity
+		for _, tier := range ev.Tiers {
+			t.Tiers = append(t.Tiers, models.TemplateTier{
+				Name: tier.Name, Description: tier.Description, Capacity: tier.Capacity,
+			})
+		}
+	}
+	if req.Title != "" {
+		t.Title = req.Title
+	}
+	if req.Description != "" {
+		t.Description = req.Description
+	}
+	if req.Capacity != 0 {
+		t.Capacity = req.Capacity
+	}
+	if len(req.Tiers) > 0 {
+		t.Tiers = nil
+		for _, tier := range req.Tiers {
+			t.Tiers = append(t.Tiers, models.TemplateTier{
+				Name: tier.Name, Description: tier.Description, Capacity: tier.Capacity,
+			})
+		}
+	}
+	if t.Title == "" {
+		return nil, errors.New("title is required unless event_id is given")
+	}
+	seen := make(map[string]bool, len(t.Tiers))
+	for i := range t.Tiers {
+		tier := &t.Tiers[i]
+		if seen[tier.Name] {
+			return nil, fmt.Errorf("duplicate tier name %q", tier.Name)
+		}
+		seen[tier.Name] = true
+		if t.Capacity > 0 && tier.Capacity > t.Capacity {
+			return nil, fmt.Errorf("tier %q capacity %d exceeds template capacity %d", tier.Name, tier.Capacity, t.Capacity)
+		}
+		tier.Position = i
+	}
+	// The lookup above is only a fast path: a concurrent save of the same
+	// name loses on the unique index.
+	if err := s.repo.Create(ctx, t); errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrTemplateExists
+	} else if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *templateService) GetTemplate(ctx context.Context, organizerID, id string) (*models.EventTemplate, error) {
+	return ownTemplate(ctx, s.repo, organizerID, id)
+}
+
+func (s *templateService) ListTemplates(ctx context.Context, organizerID string) ([]models.EventTemplate, error) {
+	return s.repo.ListByOrganizer(ctx, organizerID)
+}
+
+func (s *templateService) DeleteTemplate(ctx context.Context, organizerID, id string) error {
+	if _, err := ownTemplate(ctx, s.repo, organizerID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ownTemplate loads template id for organizerID, returning
+// ErrTemplateNotFound when it doesn't exist or belongs to someone else.
+func ownTemplate(ctx context.Context, repo repositories.TemplateRepository, organizerID, id string) (*models.EventTemplate, error) {
+	t, err := repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTemplateNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if t.OrganizerID != organizerID {
+		return nil, ErrTemplateNotFound
+	}
+	return t, nil
+}
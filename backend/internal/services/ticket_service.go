@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+var (
+	ErrTicketUnavailable = errors.New("only confirmed registrations have a ticket")
+	ErrInvalidTicket     = errors.New("ticket is invalid for this event")
+	ErrAlreadyCheckedIn  = errors.New("ticket has already been checked in")
+	ErrNotEventOrganizer = errors.New("your role on this event does not allow this")
+	ErrEventNotStarted   = errors.New("event has not started yet")
+)
+
+// ticketTokenType marks ticket JWTs. Tickets are also signed with their own
+// key (ticketKey), so neither kind of token verifies as the other.
+const ticketTokenType = "ticket"
+
+// TicketService issues signed ticket tokens for confirmed registrations and
+// verifies them at the door. Tokens are HS256 JWTs signed with a key derived
+// from JWT_SECRET and bind the registration to its current holder, so a
+// transferred or cancelled registration invalidates tokens issued before.
+type TicketService interface {
+	Ticket(ctx context.Context, userID, regID string) (*models.TicketResponse, error)
+	// TicketQR renders the ticket token as a size×size PNG QR code.
+	TicketQR(ctx context.Context, userID, regID string, size int) ([]byte, error)
+	CheckIn(ctx context.Context, organizerID, eventID, token string) (*models.Registration, error)
+	// MarkNoShows moves every confirmed registration for eventID that never
+	// checked in to no_show and reports how many. Only allowed once the event
+	// has started.
+	MarkNoShows(ctx context.Context, organizerID, eventID string) (int, error)
+}
+
+type ticketService struct {
+	db      *gorm.DB
+	regRepo repositories.RegistrationRepository
+	evtRepo repositories.EventRepository
+}
+
+func NewTicketService(db *gorm.DB, r repositories.RegistrationRepository, e repositories.EventRepository) TicketService {
+	return &ticketService{db: db, regRepo: r, evtRepo: e}
+}
+
+func (s *ticketService) Ticket(ctx context.Context, userID, regID string) (*models.TicketResponse, error) {
+	reg, err := s.ownedTicket(ctx, userID, regID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := signTicket(reg)
+	if err != nil {
+		return nil, fmt.Errorf("ticketSvc.Ticket sign: %w", err)
+	}
+	ev, err := s.evtRepo.FindByID(ctx, reg.EventID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TicketResponse{
+		RegistrationID: reg.ID, EventID: reg.EventID, Token: token,
+		QRCodeURL: fmt.Sprintf("/api/registrations/%s/ticket.png", reg.ID),
+		OnlineURL: ev.OnlineURL,
+	}, nil
+}
+
+func (s *ticketService) TicketQR(ctx context.Context, userID, regID string, size int) ([]byte, error) {
+	t, err := s.Ticket(ctx, userID, regID)
+	if err != nil {
+		return nil, err
+	}
+	png, err := qrcode.Encode(t.Token, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("ticketSvc.TicketQR encode: %w", err)
+	}
+	return png, nil
+}
+
+// CheckIn verifies a scanned token for eventID and marks its registration
+// checked in. The conditional status UPDATE rejects a second scan even when
+// two doors scan the same ticket at once.
+func (s *ticketService) CheckIn(ctx context.Context, organizerID, eventID, token string) (*models.Registration, error) {
+	if _, err := managedEvent(ctx, s.evtRepo, organizerID, eventID, accessCheckIn); err != nil {
+		return nil, err
+	}
+
+	claims, err := parseTicket(token)
+	if err != nil || claims.EventID != eventID {
+		return nil, ErrInvalidTicket
+	}
+	reg, err := s.regRepo.FindByID(ctx, claims.Subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidTicket
+	} else if err != nil {
+		return nil, err
+	}
+	if reg.EventID != eventID || reg.UserID != claims.UserID {
+		return nil, ErrInvalidTicket // transferred since the token was issued
+	}
+	if reg.Status == models.StatusCheckedIn {
+		return nil, ErrAlreadyCheckedIn
+	}
+	if !reg.Status.CanTransitionTo(models.StatusCheckedIn) {
+		return nil, ErrInvalidTicket // cancelled, refunded, …
+	}
+
+	ok, err := transition(s.db.WithContext(ctx), s.regRepo, reg, models.StatusCheckedIn, organizerID, "")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAlreadyCheckedIn // lost the race to another scanner
+	}
+	log.Printf("CHECKED IN | user=%s event=%s reg=%s", reg.UserID, eventID, reg.ID)
+	return s.regRepo.FindByID(ctx, reg.ID)
+}
+
+func (s *ticketService) MarkNoShows(ctx context.Context, organizerID, eventID string) (int, error) {
+	ev, err := managedEvent(ctx, s.evtRepo, organizerID, eventID, accessEdit)
+	if err != nil {
+		return 0, err
+	}
+	if time.Now().Before(ev.EventDate) {
+		return 0, ErrEventNotStarted
+	}
+	regs, err := s.regRepo.FindByEvent(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	marked := 0
+	txErr := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range regs {
+			if regs[i].Status != models.StatusConfirmed {
+				continue
+			}
+			ok, err := transition(tx, s.regRepo, &regs[i], models.StatusNoShow, organizerID, "")
+			if err != nil {
+				return err
+			}
+			if ok { // a scanner may have just checked them in
+				marked++
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return 0, txErr
+	}
+	log.Printf("NO-SHOWS MARKED | event=%s count=%d", eventID, marked)
+	return marked, nil
+}
+
+// ownedTicket loads regID for its holder and checks it is ticketable.
+func (s *ticketService) ownedTicket(ctx context.Context, userID, regID string) (*models.Registration, error) {
+	reg, err := s.regRepo.FindByID(ctx, regID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRegistrationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if reg.UserID != userID {
+		return nil, ErrRegistrationNotFound
+	}
+	if !reg.Status.Ticketed() {
+		return nil, ErrTicketUnavailable
+	}
+	return reg, nil
+}
+
+type ticketClaims struct {
+	Type    string `json:"typ"`
+	EventID string `json:"evt"`
+	UserID  string `json:"uid"`
+	jwt.RegisteredClaims
+}
+
+func signTicket(reg *models.Registration) (string, error) {
+	claims := ticketClaims{
+		Type: ticketTokenType, EventID: reg.EventID, UserID: reg.UserID,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: reg.ID},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ticketKey())
+}
+
+// ticketKey derives the ticket signing key from JWT_SECRET, keeping it apart
+// from the key that signs login tokens.
+func ticketKey() []byte {
+	mac := hmac.New(sha256.New, jwtSecret())
+	mac.Write([]byte(ticketTokenType))
+	return mac.Sum(nil)
+}
+
+func parseTicket(raw string) (*ticketClaims, error) {
+	var claims ticketClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		return ticketKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != ticketTokenType {
+		return nil, ErrInvalidTicket
+	}
+	return &claims, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+var (
+	ErrVenueNotFound   = errors.New("venue not found")
+	ErrInvalidTimezone = errors.New("unknown timezone")
+	ErrInvalidNear     = errors.New("near must be \"lat,lng\" in degrees")
+)
+
+type VenueService interface {
+	CreateVenue(ctx context.Context, organizerID string, req *models.CreateVenueRequest) (*models.Venue, error)
+	GetVenue(ctx context.Context, id string) (*models.Venue, error)
+	ListVenues(ctx context.Context) ([]models.Venue, error)
+}
+
+type venueService struct{ repo repositories.VenueRepository }
+
+func NewVenueService(r repositories.VenueRepository) VenueService { return &venueService{repo: r} }
+
+func (s *venueService) CreateVenue(ctx context.Context, organizerID string, req *models.CreateVenueRequest) (*models.Venue, error) {
+	if _, err := loadZone(req.Timezone); err != nil {
+		return nil, err
+	}
+	v := &models.Venue{
+		Name: req.Name, Address: req.Address, City: req.City, Country: req.Country,
+		Latitude: *req.Latitude, Longitude: *req.Longitude, Timezone: req.Timezone,
+		DefaultCapacity: req.DefaultCapacity, CreatedBy: organizerID,
+	}
+	if err := s.repo.Create(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *venueService) GetVenue(ctx context.Context, id string) (*models.Venue, error) {
+	return findVenue(ctx, s.repo, id)
+}
+
+func (s *venueService) ListVenues(ctx context.Context) ([]models.Venue, error) {
+	return s.repo.List(ctx)
+}
+
+// findVenue maps a missing venue to ErrVenueNotFound.
+func findVenue(ctx context.Context, repo repositories.VenueRepository, id string) (*models.Venue, error) {
+	v, err := repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVenueNotFound
+	}
+	return v, err
+}
+
+// parseNear parses a near= value, "lat,lng" in degrees.
+func parseNear(s string) (*models.GeoPoint, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, ErrInvalidNear
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err1 != nil || err2 != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, ErrInvalidNear
+	}
+	return &models.GeoPoint{Lat: lat, Lng: lng}, nil
+}
@@ -0,0 +1,127 @@
+// Package storage keeps uploaded files (event images) behind BlobStore, so
+// the services never touch the filesystem or a cloud SDK directly.
+//
+// The only backend so far is LocalStore, a directory on the server's disk,
+// picked by MEDIA_DIR. A shared backend (S3, GCS …) is needed before running
+// more than one node.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned by Get for a key that isn't stored.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque files by key. Keys are slash-separated paths such
+// as "events/<id>/<media id>/thumb.jpg".
+type BlobStore interface {
+	// Put stores r under key, replacing any existing blob.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get opens the blob under key; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// FromEnv builds the LocalStore rooted at MEDIA_DIR (default ./media),
+// creating the directory if needed.
+func FromEnv() (BlobStore, error) {
+	dir := os.Getenv("MEDIA_DIR")
+	if dir == "" {
+		dir = "media"
+	}
+	return NewLocalStore(dir)
+}
+
+// LocalStore keeps blobs as files under a directory.
+type LocalStore struct{ dir string }
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage.NewLocalStore: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put writes to a temporary file and renames it into place, so a reader
+// never sees a half-written blob.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("storage.Put: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage.Put: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := io.Copy(tmp, ctxReader{ctx, r}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("storage.Put: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("storage.Put: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("storage.Put: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("storage.Get: %w", err)
+	}
+	return f, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("storage.Delete: %w", err)
+	}
+	return nil
+}
+
+// path maps key into the store's directory, refusing keys that would
+// escape it.
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
+
+// ctxReader stops a copy once ctx is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
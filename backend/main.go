@@ -20,7 +20,10 @@ func main() {
 	}
 
 	// Start HTTP server
-	srv := server.New(db)
+	srv, err := server.New(db)
+	if err != nil {
+		log.Fatalf("Failed to configure server: %v", err)
+	}
 	if err := srv.Run(); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
@@ -6,6 +6,8 @@
 package tests
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -18,6 +20,7 @@ import (
 	"gorm.io/gorm/logger"
 
 	"github.com/Amrutavarshini24/Eventregistration/internal/database"
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
 	"github.com/Amrutavarshini24/Eventregistration/internal/models"
 	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
 	"github.com/Amrutavarshini24/Eventregistration/internal/services"
@@ -31,12 +34,26 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("open test db: %v", err)
 	}
+	// Every connection to ":memory:" is a separate, empty database; pin the
+	// pool to one so concurrent goroutines all see the migrated schema.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("test db handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
 	if err := database.Migrate(db); err != nil {
 		t.Fatalf("migrate test db: %v", err)
 	}
 	return db
 }
 
+// newBookingService wires a BookingService with the in-memory locker the
+// server uses by default.
+func newBookingService(db *gorm.DB, regRepo repositories.RegistrationRepository, eventRepo repositories.EventRepository) services.BookingService {
+	return services.NewBookingService(db, regRepo, eventRepo, repositories.NewUserRepository(db),
+		locking.NewMemoryLocker(5*time.Second))
+}
+
 func createTestUser(t *testing.T, db *gorm.DB, index int) string {
 	t.Helper()
 	u := &models.User{
@@ -64,6 +81,7 @@ func createTestEvent(t *testing.T, db *gorm.DB, organizerID string, capacity int
 // TestConcurrentBooking spawns 50 goroutines racing for 5 seats.
 // Asserts: exactly 5 succeed, 45 fail, no data races.
 func TestConcurrentBooking(t *testing.T) {
+	ctx := context.Background()
 	const (
 		totalUsers = 50
 		capacity   = 5
@@ -72,7 +90,7 @@ func TestConcurrentBooking(t *testing.T) {
 
 	eventRepo := repositories.NewEventRepository(db)
 	regRepo   := repositories.NewRegistrationRepository(db)
-	svc       := services.NewBookingService(db, regRepo, eventRepo)
+	svc       := newBookingService(db, regRepo, eventRepo)
 
 	org := &models.User{Name: "Organizer", Email: "org@test.com", PasswordHash: "h", Role: "organizer"}
 	db.Create(org)
@@ -97,7 +115,7 @@ func TestConcurrentBooking(t *testing.T) {
 			defer wg.Done()
 			<-startGun
 			log.Printf("USER %s attempted booking for event %s", uid, eventID)
-			_, err := svc.Book(uid, eventID)
+			_, err := svc.Book(ctx, uid, eventID, nil)
 			if err == nil {
 				atomic.AddInt64(&successCnt, 1)
 				results[idx] = fmt.Sprintf("✅ User %d — SEAT RESERVED SUCCESSFULLY", idx)
@@ -135,10 +153,11 @@ func TestConcurrentBooking(t *testing.T) {
 
 // TestNoDuplicateBooking verifies the same user cannot book twice.
 func TestNoDuplicateBooking(t *testing.T) {
+	ctx := context.Background()
 	db := setupTestDB(t)
 	eventRepo := repositories.NewEventRepository(db)
 	regRepo   := repositories.NewRegistrationRepository(db)
-	svc       := services.NewBookingService(db, regRepo, eventRepo)
+	svc       := newBookingService(db, regRepo, eventRepo)
 
 	org := &models.User{Name: "Org", Email: "org2@t.com", PasswordHash: "h", Role: "organizer"}
 	db.Create(org)
@@ -147,12 +166,421 @@ func TestNoDuplicateBooking(t *testing.T) {
 	ev := &models.Event{Title: "Dup Test", Capacity: 10, EventDate: time.Now().Add(time.Hour), OrganizerID: org.ID}
 	db.Create(ev)
 
-	if _, err := svc.Book(att.ID, ev.ID); err != nil {
+	if _, err := svc.Book(ctx, att.ID, ev.ID, nil); err != nil {
 		t.Fatalf("first booking failed: %v", err)
 	}
-	if _, err := svc.Book(att.ID, ev.ID); err == nil {
+	if _, err := svc.Book(ctx, att.ID, ev.ID, nil); err == nil {
 		t.Fatal("duplicate booking was not rejected")
 	} else {
 		t.Logf("✅ Duplicate rejected: %v", err)
 	}
 }
+
+// TestCancelReleasesSeat verifies cancelling frees the seat and that a user
+// can cancel, rebook and cancel again without tripping the unique index.
+func TestCancelReleasesSeat(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org3@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	att := createTestUser(t, db, 1)
+	eventID := createTestEvent(t, db, org.ID, 1)
+
+	for round := 1; round <= 2; round++ {
+		if _, err := svc.Book(ctx, att, eventID, nil); err != nil {
+			t.Fatalf("round %d: booking failed: %v", round, err)
+		}
+		if _, err := svc.Cancel(ctx, att, eventID); err != nil {
+			t.Fatalf("round %d: cancel failed: %v", round, err)
+		}
+	}
+	if _, err := svc.Cancel(ctx, att, eventID); err != services.ErrRegistrationNotFound {
+		t.Errorf("cancel without booking: got %v, want ErrRegistrationNotFound", err)
+	}
+
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 0 {
+		t.Errorf("event.Registered = %d, want 0", ev.Registered)
+	}
+	var cancelled int64
+	db.Model(&models.Registration{}).Where("event_id = ? AND status = ?", eventID, models.StatusCancelled).Count(&cancelled)
+	if cancelled != 2 {
+		t.Errorf("cancelled rows = %d, want 2", cancelled)
+	}
+}
+
+// TestWaitlistPromotion fills a one-seat event, queues two users and checks
+// that cancelling promotes the head of the queue in FIFO order.
+func TestWaitlistPromotion(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org4@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	eventID := createTestEvent(t, db, org.ID, 1)
+	holder, first, second := createTestUser(t, db, 1), createTestUser(t, db, 2), createTestUser(t, db, 3)
+
+	if _, err := svc.Book(ctx, holder, eventID, nil); err != nil {
+		t.Fatalf("booking failed: %v", err)
+	}
+	if _, err := svc.Book(ctx, first, eventID, nil); err != services.ErrEventFull {
+		t.Fatalf("without opt-in: got %v, want ErrEventFull", err)
+	}
+	for i, uid := range []string{first, second} {
+		reg, err := svc.Book(ctx, uid, eventID, &models.BookingRequest{JoinWaitlist: true})
+		if err != nil {
+			t.Fatalf("waitlist join failed: %v", err)
+		}
+		if reg.Status != models.StatusWaitlisted || reg.WaitlistPosition != i+1 {
+			t.Fatalf("got status=%s position=%d, want waitlisted #%d", reg.Status, reg.WaitlistPosition, i+1)
+		}
+		time.Sleep(2 * time.Millisecond) // distinct created_at for FIFO order
+	}
+
+	if _, err := svc.Cancel(ctx, holder, eventID); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	promoted, err := regRepo.FindByUserAndEvent(ctx, first, eventID)
+	if err != nil || promoted.Status != models.StatusConfirmed {
+		t.Fatalf("first in queue not promoted: %+v, %v", promoted, err)
+	}
+	regs, _ := svc.GetUserRegistrations(ctx, second)
+	if len(regs) != 1 || regs[0].WaitlistPosition != 1 {
+		t.Fatalf("second in queue should now be #1, got %+v", regs)
+	}
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 1 {
+		t.Errorf("event.Registered = %d, want 1", ev.Registered)
+	}
+}
+
+// TestGroupBookingAllOrNothing verifies a group claims all its seats in one
+// step, or none of them when they don't fit.
+func TestGroupBookingAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org5@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	eventID := createTestEvent(t, db, org.ID, 3)
+	family, team := createTestUser(t, db, 1), createTestUser(t, db, 2)
+
+	reg, err := svc.Book(ctx, family, eventID, &models.BookingRequest{
+		Quantity:  2,
+		Attendees: []models.AttendeeRequest{{Name: "Parent"}, {Name: "Child"}},
+	})
+	if err != nil {
+		t.Fatalf("group booking failed: %v", err)
+	}
+	if reg.Quantity != 2 || len(reg.Attendees) != 2 {
+		t.Fatalf("got quantity=%d attendees=%d, want 2/2", reg.Quantity, len(reg.Attendees))
+	}
+
+	_, err = svc.Book(ctx, team, eventID, &models.BookingRequest{
+		Attendees: []models.AttendeeRequest{{Name: "Ann"}, {Name: "Bob"}},
+	})
+	if err != services.ErrEventFull {
+		t.Fatalf("2 seats into 1 free: got %v, want ErrEventFull", err)
+	}
+	if _, err := svc.Book(ctx, team, eventID, &models.BookingRequest{Quantity: 2}); !errors.Is(err, services.ErrInvalidQuantity) {
+		t.Errorf("unnamed group: got %v, want ErrInvalidQuantity", err)
+	}
+
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 2 {
+		t.Errorf("event.Registered = %d, want 2", ev.Registered)
+	}
+
+	if _, err := svc.Cancel(ctx, family, eventID); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 0 {
+		t.Errorf("after cancel event.Registered = %d, want 0", ev.Registered)
+	}
+}
+
+// TestTierCapacityIsIndependent verifies each tier enforces its own capacity
+// while the event total still counts every tier.
+func TestTierCapacityIsIndependent(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org6@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	ev := &models.Event{
+		Title: "Tiered", Capacity: 10, EventDate: time.Now().Add(time.Hour), OrganizerID: org.ID,
+		Tiers: []models.TicketTier{{Name: "VIP", Capacity: 1}, {Name: "General", Capacity: 9}},
+	}
+	if err := db.Create(ev).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	vip, general := ev.Tiers[0].ID, ev.Tiers[1].ID
+	a, b := createTestUser(t, db, 1), createTestUser(t, db, 2)
+
+	if _, err := svc.Book(ctx, a, ev.ID, nil); err != services.ErrTierRequired {
+		t.Fatalf("no tier: got %v, want ErrTierRequired", err)
+	}
+	if _, err := svc.Book(ctx, a, ev.ID, &models.BookingRequest{TierID: vip}); err != nil {
+		t.Fatalf("VIP booking failed: %v", err)
+	}
+	if _, err := svc.Book(ctx, b, ev.ID, &models.BookingRequest{TierID: vip}); err != services.ErrTierSoldOut {
+		t.Fatalf("second VIP: got %v, want ErrTierSoldOut", err)
+	}
+	if _, err := svc.Book(ctx, b, ev.ID, &models.BookingRequest{TierID: general}); err != nil {
+		t.Fatalf("General booking failed: %v", err)
+	}
+
+	var got models.Event
+	db.Preload("Tiers").First(&got, "id = ?", ev.ID)
+	if got.Registered != 2 {
+		t.Errorf("event.Registered = %d, want 2 (failed VIP claim must roll back)", got.Registered)
+	}
+	for _, tier := range got.Tiers {
+		if tier.Registered != 1 {
+			t.Errorf("tier %s registered = %d, want 1", tier.Name, tier.Registered)
+		}
+	}
+}
+
+// TestSeatHoldExpiry verifies a hold occupies its seat until the sweeper
+// returns it, and that an expired hold can no longer be confirmed.
+func TestSeatHoldExpiry(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("HOLD_TTL", "20ms")
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org7@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	eventID := createTestEvent(t, db, org.ID, 1)
+	holder, other := createTestUser(t, db, 1), createTestUser(t, db, 2)
+
+	hold, err := svc.Hold(ctx, holder, eventID, nil)
+	if err != nil {
+		t.Fatalf("hold failed: %v", err)
+	}
+	if _, err := svc.Book(ctx, other, eventID, nil); err != services.ErrEventFull {
+		t.Fatalf("booking over an active hold: got %v, want ErrEventFull", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if n, err := svc.ExpireHolds(ctx); err != nil || n != 1 {
+		t.Fatalf("ExpireHolds = %d, %v; want 1, nil", n, err)
+	}
+	if _, err := svc.ConfirmHold(ctx, holder, hold.ID); err != services.ErrNotHeld {
+		t.Errorf("confirming a swept hold: got %v, want ErrNotHeld", err)
+	}
+	if _, err := svc.Book(ctx, other, eventID, nil); err != nil {
+		t.Fatalf("booking after expiry failed: %v", err)
+	}
+}
+
+// TestSeatHoldConfirm verifies a confirmed hold becomes a normal booking.
+func TestSeatHoldConfirm(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org8@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	eventID := createTestEvent(t, db, org.ID, 2)
+	holder, stranger := createTestUser(t, db, 1), createTestUser(t, db, 2)
+
+	hold, err := svc.Hold(ctx, holder, eventID, nil)
+	if err != nil {
+		t.Fatalf("hold failed: %v", err)
+	}
+	if _, err := svc.ConfirmHold(ctx, stranger, hold.ID); err != services.ErrRegistrationNotFound {
+		t.Errorf("confirming someone else's hold: got %v, want ErrRegistrationNotFound", err)
+	}
+	reg, err := svc.ConfirmHold(ctx, holder, hold.ID)
+	if err != nil || reg.Status != models.StatusConfirmed {
+		t.Fatalf("confirm = %+v, %v", reg, err)
+	}
+	if n, _ := svc.ExpireHolds(ctx); n != 0 {
+		t.Errorf("sweeper released %d confirmed bookings", n)
+	}
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 1 {
+		t.Errorf("event.Registered = %d, want 1", ev.Registered)
+	}
+}
+
+// TestTransferRegistration verifies a confirmed registration moves to the
+// recipient with history, and that a recipient who is already registered is
+// refused by the duplicate-booking rule.
+func TestTransferRegistration(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org10@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	eventID := createTestEvent(t, db, org.ID, 5)
+	from, to, taken := createTestUser(t, db, 1), createTestUser(t, db, 2), createTestUser(t, db, 3)
+
+	reg, err := svc.Book(ctx, from, eventID, nil)
+	if err != nil {
+		t.Fatalf("booking failed: %v", err)
+	}
+	if _, err := svc.Book(ctx, taken, eventID, nil); err != nil {
+		t.Fatalf("booking failed: %v", err)
+	}
+	if _, err := svc.Transfer(ctx, from, reg.ID, "user3@test.com"); err != services.ErrDuplicateBooking {
+		t.Errorf("transfer to registered user: got %v, want ErrDuplicateBooking", err)
+	}
+
+	moved, err := svc.Transfer(ctx, from, reg.ID, "user2@test.com")
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	if moved.UserID != to {
+		t.Errorf("registration holder = %s, want %s", moved.UserID, to)
+	}
+	if _, err := svc.Transfer(ctx, from, reg.ID, "user2@test.com"); err != services.ErrRegistrationNotFound {
+		t.Errorf("transfer by previous holder: got %v, want ErrRegistrationNotFound", err)
+	}
+	regs, _ := svc.GetUserRegistrations(ctx, to)
+	if len(regs) != 1 || len(regs[0].Transfers) != 1 || regs[0].Transfers[0].FromUserID != from {
+		t.Errorf("transfer history not recorded: %+v", regs)
+	}
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 2 {
+		t.Errorf("event.Registered = %d, want 2", ev.Registered)
+	}
+}
+
+// TestSalesWindow verifies bookings outside the sales window are refused and
+// that sales close at the event start by default.
+func TestSalesWindow(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	svc       := newBookingService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org11@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	att := createTestUser(t, db, 1)
+	opensLater := time.Now().Add(time.Hour)
+	events := map[string]*models.Event{
+		"already started": {Title: "Past", Capacity: 5, EventDate: time.Now().Add(-time.Minute), OrganizerID: org.ID},
+		"not yet open": {
+			Title: "Soon", Capacity: 5, EventDate: time.Now().Add(48 * time.Hour), OrganizerID: org.ID,
+			SalesOpenAt: &opensLater,
+		},
+	}
+	for name, ev := range events {
+		db.Create(ev)
+		if _, err := svc.Book(ctx, att, ev.ID, nil); err != services.ErrSalesClosed {
+			t.Errorf("%s: got %v, want ErrSalesClosed", name, err)
+		}
+	}
+}
+
+// TestMemoryLockerTimeout verifies a contended lock gives up after the
+// timeout and that released keys can be taken again.
+func TestMemoryLockerTimeout(t *testing.T) {
+	ctx := context.Background()
+	l := locking.NewMemoryLocker(20 * time.Millisecond)
+	unlock, err := l.Lock(ctx, "evt")
+	if err != nil {
+		t.Fatalf("first lock: %v", err)
+	}
+	if _, err := l.Lock(ctx, "evt"); err != locking.ErrTimeout {
+		t.Fatalf("contended lock: got %v, want ErrTimeout", err)
+	}
+	if other, err := l.Lock(ctx, "other-evt"); err != nil {
+		t.Fatalf("independent key blocked: %v", err)
+	} else {
+		other()
+	}
+	unlock()
+	unlock() // idempotent
+	again, err := l.Lock(ctx, "evt")
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	again()
+}
+
+// TestBookRespectsDeadline verifies a booking waiting on a busy event gives
+// up with ErrTimeout when its context deadline passes, well before the
+// locker's own timeout.
+func TestBookRespectsDeadline(t *testing.T) {
+	db := setupTestDB(t)
+	l := locking.NewMemoryLocker(5 * time.Second)
+	svc := services.NewBookingService(db, repositories.NewRegistrationRepository(db),
+		repositories.NewEventRepository(db), repositories.NewUserRepository(db), l)
+
+	org := createTestUser(t, db, 1)
+	att := createTestUser(t, db, 2)
+	eventID := createTestEvent(t, db, org, 5)
+
+	unlock, err := l.Lock(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := svc.Book(ctx, att, eventID, nil); !errors.Is(err, services.ErrTimeout) {
+		t.Fatalf("got %v, want ErrTimeout", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("Book waited %v; the request deadline should have cut it short", waited)
+	}
+}
+
+// TestEventRegistrationsOrganizerOnly verifies only the event's organizer can
+// read its attendee list.
+func TestEventRegistrationsOrganizerOnly(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	svc := newBookingService(db, repositories.NewRegistrationRepository(db), repositories.NewEventRepository(db))
+
+	org := createTestUser(t, db, 1)
+	att := createTestUser(t, db, 2)
+	eventID := createTestEvent(t, db, org, 5)
+	if _, err := svc.Book(ctx, att, eventID, nil); err != nil {
+		t.Fatalf("book: %v", err)
+	}
+
+	if _, err := svc.GetEventRegistrations(ctx, att, eventID); err != services.ErrNotEventOrganizer {
+		t.Errorf("attendee: got %v, want ErrNotEventOrganizer", err)
+	}
+	if _, err := svc.GetEventRegistrations(ctx, org, "no-such-event"); err != services.ErrEventNotFound {
+		t.Errorf("missing event: got %v, want ErrEventNotFound", err)
+	}
+	regs, err := svc.GetEventRegistrations(ctx, org, eventID)
+	if err != nil || len(regs) != 1 || regs[0].UserID != att {
+		t.Fatalf("organizer: got %d registrations, %v", len(regs), err)
+	}
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// TestEventCollaborators invites a co-organizer, staff and a viewer to an
+// event and checks what each role may do, then changes and revokes roles.
+func TestEventCollaborators(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	n := &recordingNotifier{}
+	booking, events, regRepo := newEventServices(db, n)
+	eventRepo := repositories.NewEventRepository(db)
+	tickets   := services.NewTicketService(db, regRepo, eventRepo)
+	stats     := services.NewStatsService(eventRepo, repositories.NewStatsRepository(db))
+	collabs   := services.NewCollaboratorService(eventRepo, repositories.NewCollaboratorRepository(db),
+		repositories.NewUserRepository(db), n)
+
+	org, coOrg, staff, viewer, stranger := createTestUser(t, db, 1), createTestUser(t, db, 2),
+		createTestUser(t, db, 3), createTestUser(t, db, 4), createTestUser(t, db, 5)
+	guest := createTestUser(t, db, 10)
+	ev := createTestEvent(t, db, org, 10)
+
+	invite := func(by, email string, role models.CollaboratorRole) error {
+		_, err := collabs.Invite(ctx, by, ev, &models.InviteCollaboratorRequest{Email: email, Role: role})
+		return err
+	}
+	if err := invite(org, "user2@test.com", models.RoleCoOrganizer); err != nil {
+		t.Fatalf("invite co-organizer: %v", err)
+	}
+	// A co-organizer may invite others.
+	if err := invite(coOrg, "user3@test.com", models.RoleStaff); err != nil {
+		t.Fatalf("co-organizer invites staff: %v", err)
+	}
+	if err := invite(org, "user4@test.com", models.RoleViewer); err != nil {
+		t.Fatalf("invite viewer: %v", err)
+	}
+	if err := invite(staff, "user5@test.com", models.RoleViewer); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("staff invites: got %v, want ErrNotEventOrganizer", err)
+	}
+	if err := invite(org, "nobody@test.com", models.RoleStaff); !errors.Is(err, services.ErrInviteeNotFound) {
+		t.Errorf("unknown email: got %v, want ErrInviteeNotFound", err)
+	}
+	if err := invite(coOrg, "user1@test.com", models.RoleViewer); !errors.Is(err, services.ErrInviteOrganizer) {
+		t.Errorf("inviting the organizer: got %v, want ErrInviteOrganizer", err)
+	}
+	invited := 0
+	for _, e := range n.events {
+		if _, ok := e.(notify.CollaboratorInvited); ok {
+			invited++
+		}
+	}
+	if invited != 3 {
+		t.Errorf("got %d invitation notices, want 3", invited)
+	}
+
+	// Staff: check-in and the attendee list, nothing else.
+	reg, err := booking.Book(ctx, guest, ev, nil)
+	if err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	ticket, err := tickets.Ticket(ctx, guest, reg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tickets.CheckIn(ctx, staff, ev, ticket.Token); err != nil {
+		t.Errorf("staff check-in: %v", err)
+	}
+	if regs, err := booking.GetEventRegistrations(ctx, staff, ev); err != nil || len(regs) != 1 {
+		t.Errorf("staff attendee list = %d, %v; want 1", len(regs), err)
+	}
+	title := "Renamed by staff"
+	if _, err := events.UpdateEvent(ctx, staff, ev, &models.UpdateEventRequest{Title: &title}); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("staff update: got %v, want ErrNotEventOrganizer", err)
+	}
+
+	// Viewer: stats and the collaborator list, not attendees.
+	if _, err := stats.EventStats(ctx, viewer, ev, &models.StatsQuery{}); err != nil {
+		t.Errorf("viewer stats: %v", err)
+	}
+	if cs, err := collabs.List(ctx, viewer, ev); err != nil || len(cs) != 3 || cs[0].User == nil {
+		t.Errorf("viewer collaborator list = %+v, %v; want 3 with users", cs, err)
+	}
+	if _, err := booking.GetEventRegistrations(ctx, viewer, ev); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("viewer attendee list: got %v, want ErrNotEventOrganizer", err)
+	}
+	if _, err := collabs.List(ctx, stranger, ev); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("stranger collaborator list: got %v, want ErrNotEventOrganizer", err)
+	}
+
+	// Co-organizer: edits, but only the organizer deletes.
+	title = "Renamed by co-organizer"
+	if resp, err := events.UpdateEvent(ctx, coOrg, ev, &models.UpdateEventRequest{Title: &title}); err != nil || resp.Title != title {
+		t.Errorf("co-organizer update: %v", err)
+	}
+	if err := events.DeleteEvent(ctx, coOrg, ev); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("co-organizer delete: got %v, want ErrNotEventOrganizer", err)
+	}
+
+	// The dashboard covers events the user collaborates on.
+	if all, err := stats.OrganizerStats(ctx, coOrg, &models.StatsQuery{}); err != nil || len(all.Events) != 1 {
+		t.Errorf("co-organizer dashboard: %+v, %v; want the shared event", all, err)
+	}
+	if mine, err := collabs.Collaborations(ctx, viewer); err != nil || len(mine) != 1 || mine[0].Event == nil || mine[0].Role != models.RoleViewer {
+		t.Errorf("viewer collaborations = %+v, %v", mine, err)
+	}
+
+	// Re-inviting changes the role; removing revokes access.
+	if err := invite(org, "user4@test.com", models.RoleStaff); err != nil {
+		t.Fatalf("re-invite: %v", err)
+	}
+	if _, err := booking.GetEventRegistrations(ctx, viewer, ev); err != nil {
+		t.Errorf("promoted viewer attendee list: %v", err)
+	}
+	if err := collabs.Remove(ctx, viewer, ev, staff); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("staff removes staff: got %v, want ErrNotEventOrganizer", err)
+	}
+	if err := collabs.Remove(ctx, coOrg, ev, staff); err != nil {
+		t.Fatalf("remove staff: %v", err)
+	}
+	if _, err := tickets.CheckIn(ctx, staff, ev, ticket.Token); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("removed staff check-in: got %v, want ErrNotEventOrganizer", err)
+	}
+	if err := collabs.Remove(ctx, viewer, ev, viewer); err != nil {
+		t.Errorf("leave event: %v", err)
+	}
+	if err := collabs.Remove(ctx, org, ev, viewer); !errors.Is(err, services.ErrCollaboratorNotFound) {
+		t.Errorf("remove twice: got %v, want ErrCollaboratorNotFound", err)
+	}
+}
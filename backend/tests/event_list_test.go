@@ -0,0 +1,292 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/handlers"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// listEvents serves GET /events through EventHandler and decodes the page.
+func listEvents(t *testing.T, db *gorm.DB, target string) (int, models.EventPage) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	_, events, _ := newEventServices(db, notify.LogNotifier{})
+	r := gin.New()
+	r.GET("/events", handlers.NewEventHandler(events).ListEvents)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	var page models.EventPage
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode %s: %v", target, err)
+		}
+	}
+	return w.Code, page
+}
+
+// TestListEventsPagination walks the list page by page through the next links
+// and checks filters and sorting.
+func TestListEventsPagination(t *testing.T) {
+	db := setupTestDB(t)
+	org := createTestUser(t, db, 1)
+	other := createTestUser(t, db, 2)
+
+	// Five upcoming events a day apart, one of them full, plus one in the
+	// past and one by another organizer.
+	for i := 1; i <= 5; i++ {
+		ev := &models.Event{Title: string(rune('A' + i)), Capacity: 10, OrganizerID: org,
+			EventDate: time.Now().Add(time.Duration(i) * 24 * time.Hour)}
+		if i == 3 {
+			ev.Registered = 10
+		}
+		db.Create(ev)
+	}
+	db.Create(&models.Event{Title: "Past", Capacity: 10, OrganizerID: org, EventDate: time.Now().Add(-24 * time.Hour)})
+	db.Create(&models.Event{Title: "Other", Capacity: 10, OrganizerID: other, EventDate: time.Now().Add(96 * time.Hour)})
+
+	var seen []string
+	next := "/events?organizer_id=" + org + "&upcoming=true&available=true&sort=-date&limit=2"
+	for pages := 0; next != ""; pages++ {
+		if pages > 3 {
+			t.Fatalf("next links never ran out")
+		}
+		code, page := listEvents(t, db, next)
+		if code != http.StatusOK || page.Total != 4 || page.Count > 2 {
+			t.Fatalf("GET %s = %d, total=%d count=%d", next, code, page.Total, page.Count)
+		}
+		for _, ev := range page.Events {
+			seen = append(seen, ev.Title)
+		}
+		next = page.Next
+	}
+	if got := len(seen); got != 4 || seen[0] != "F" || seen[3] != "B" {
+		t.Errorf("walked titles = %v, want F E C B without the full, past or foreign events", seen)
+	}
+
+	from := time.Now().Add(36 * time.Hour).UTC().Format(time.RFC3339)
+	if _, page := listEvents(t, db, "/events?from="+from+"&sort=title"); page.Total != 5 || page.Events[0].Title != "C" {
+		t.Errorf("from filter: total=%d first=%+v", page.Total, page.Events)
+	}
+	for _, bad := range []string{"/events?sort=popularity", "/events?limit=500", "/events?from=tomorrow"} {
+		if code, _ := listEvents(t, db, bad); code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", bad, code)
+		}
+	}
+}
+
+// TestSearchEvents checks ranking, highlighting, paging and that the index
+// follows edits, drafts and deletes.
+func TestSearchEvents(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	_, events, _ := newEventServices(db, notify.LogNotifier{})
+	org := createTestUser(t, db, 1)
+
+	create := func(title, desc string) string {
+		ev, err := events.CreateEvent(ctx, &models.CreateEventRequest{
+			Title: title, Description: desc, Capacity: 10,
+			EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		}, org)
+		if err != nil {
+			t.Fatalf("create %q: %v", title, err)
+		}
+		return ev.ID
+	}
+	create("Knitting circle", "Bring needles. Golang developers welcome <3")
+	goID := create("Golang meetup", "Talks on concurrency in Go.")
+	create("Gardening", "Tomatoes and more tomatoes.")
+	events.CreateEvent(ctx, &models.CreateEventRequest{Title: "Golang draft", Capacity: 5, Status: "draft",
+		EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339)}, org)
+
+	page, err := events.SearchEvents(ctx, &models.EventSearchQuery{Q: "golang!", Limit: 1})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if page.Total != 2 || page.Count != 1 || page.Results[0].ID != goID {
+		t.Fatalf("title match should rank first of 2: total=%d results=%+v", page.Total, page.Results)
+	}
+	if got := page.Results[0].Highlight.Title; got != "<mark>Golang</mark> meetup" {
+		t.Errorf("title highlight = %q", got)
+	}
+	page, _ = events.SearchEvents(ctx, &models.EventSearchQuery{Q: "golang", Limit: 1, Offset: 1})
+	if page.Count != 1 || !strings.Contains(page.Results[0].Highlight.Description, "<mark>Golang</mark> developers welcome &lt;3") {
+		t.Errorf("second page = %+v", page.Results)
+	}
+
+	if page, _ := events.SearchEvents(ctx, &models.EventSearchQuery{Q: "tomato"}); page.Total != 1 {
+		t.Errorf("prefix/stemmed search for tomato: total=%d", page.Total)
+	}
+	if page, _ := events.SearchEvents(ctx, &models.EventSearchQuery{Q: `"OR * -)`}); page.Total != 0 {
+		t.Errorf("operator-only query matched %d events", page.Total)
+	}
+
+	title := "Rust meetup"
+	if _, err := events.UpdateEvent(ctx, org, goID, &models.UpdateEventRequest{Title: &title}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if page, _ := events.SearchEvents(ctx, &models.EventSearchQuery{Q: "rust"}); page.Total != 1 {
+		t.Errorf("renamed event not found by its new title")
+	}
+	if err := events.DeleteEvent(ctx, org, goID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if page, _ := events.SearchEvents(ctx, &models.EventSearchQuery{Q: "rust"}); page.Total != 0 {
+		t.Errorf("deleted event still found")
+	}
+}
+
+// TestCategoriesAndTags labels events, filters the list by label and checks
+// the counts only include listed events.
+func TestCategoriesAndTags(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	_, events, _ := newEventServices(db, notify.LogNotifier{})
+	tax := services.NewTaxonomyService(repositories.NewTaxonomyRepository(db))
+	org := createTestUser(t, db, 1)
+
+	music, err := tax.CreateCategory(ctx, org, &models.CreateCategoryRequest{Name: "Live Music"})
+	if err != nil || music.Slug != "live-music" {
+		t.Fatalf("create category = %+v, %v", music, err)
+	}
+	tax.CreateCategory(ctx, org, &models.CreateCategoryRequest{Name: "Tech"})
+	if _, err := tax.CreateCategory(ctx, org, &models.CreateCategoryRequest{Name: "live music!"}); err != services.ErrCategoryExists {
+		t.Errorf("duplicate slug: got %v, want ErrCategoryExists", err)
+	}
+
+	create := func(title, status string, cats, tags []string) error {
+		_, err := events.CreateEvent(ctx, &models.CreateEventRequest{
+			Title: title, Capacity: 10, Status: status, Categories: cats, Tags: tags,
+			EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		}, org)
+		return err
+	}
+	if err := create("Jazz night", "", []string{"live-music"}, []string{"Jazz", "outdoor ", "jazz"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	create("Rock night", "", []string{"live-music"}, []string{"outdoor"})
+	create("Go meetup", "", []string{"tech"}, []string{"golang"})
+	create("Secret gig", "draft", []string{"live-music"}, []string{"jazz"})
+	if err := create("Bad", "", []string{"cooking"}, nil); !errors.Is(err, services.ErrUnknownCategory) {
+		t.Errorf("unknown category: got %v", err)
+	}
+
+	page, _ := events.ListEvents(ctx, &models.EventListQuery{Category: "live-music", Tag: "JAZZ"})
+	if page.Total != 1 || page.Events[0].Title != "Jazz night" {
+		t.Fatalf("category+tag filter: %+v", page.Events)
+	}
+	if ev := page.Events[0]; len(ev.Tags) != 2 || len(ev.Categories) != 1 || ev.Categories[0].Slug != "live-music" {
+		t.Errorf("labels = %+v %+v", ev.Categories, ev.Tags)
+	}
+	if page, _ := events.ListEvents(ctx, &models.EventListQuery{Tag: "outdoor"}); page.Total != 2 {
+		t.Errorf("tag filter: total=%d", page.Total)
+	}
+
+	got, err := tax.Taxonomy(ctx)
+	if err != nil {
+		t.Fatalf("taxonomy: %v", err)
+	}
+	counts := map[string]int64{}
+	for _, c := range got.Categories {
+		counts[c.Slug] = c.EventCount
+	}
+	if counts["live-music"] != 2 || counts["tech"] != 1 {
+		t.Errorf("category counts = %v (drafts must not count)", counts)
+	}
+	if len(got.PopularTags) != 3 || got.PopularTags[0].Name != "outdoor" || got.PopularTags[0].EventCount != 2 {
+		t.Errorf("popular tags = %+v", got.PopularTags)
+	}
+}
+
+// TestNearFilter places events at venues around Berlin and online, and checks
+// the radius filter, distance sort and venue defaults.
+func TestNearFilter(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	_, events, _ := newEventServices(db, notify.LogNotifier{})
+	venues := services.NewVenueService(repositories.NewVenueRepository(db))
+	org := createTestUser(t, db, 1)
+
+	venue := func(name string, lat, lng float64) string {
+		v, err := venues.CreateVenue(ctx, org, &models.CreateVenueRequest{
+			Name: name, Address: "Somewhere 1", Latitude: &lat, Longitude: &lng,
+			Timezone: "Europe/Berlin", DefaultCapacity: 300,
+		})
+		if err != nil {
+			t.Fatalf("create venue %s: %v", name, err)
+		}
+		return v.ID
+	}
+	lat, lng := 52.5, 13.4
+	if _, err := venues.CreateVenue(ctx, org, &models.CreateVenueRequest{Name: "Nowhere", Address: "x",
+		Latitude: &lat, Longitude: &lng, Timezone: "Mars/Olympus"}); !errors.Is(err, services.ErrInvalidTimezone) {
+		t.Errorf("bad timezone: got %v", err)
+	}
+
+	create := func(title, venueID string, online bool) *models.EventResponse {
+		ev, err := events.CreateEvent(ctx, &models.CreateEventRequest{
+			Title: title, VenueID: venueID, Online: online,
+			EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+		}, org)
+		if err != nil {
+			t.Fatalf("create %s: %v", title, err)
+		}
+		return ev
+	}
+	if ev := create("Berlin", venue("Tempodrom", 52.5010, 13.3807), false); ev.Capacity != 300 {
+		t.Errorf("capacity = %d, want the venue default 300", ev.Capacity)
+	}
+	create("Potsdam", venue("Nikolaisaal", 52.3966, 13.0586), false)
+	create("Hamburg", venue("Elbphilharmonie", 53.5413, 9.9841), false)
+	if _, err := events.CreateEvent(ctx, &models.CreateEventRequest{Title: "Webinar", Online: true,
+		EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339)}, org); err == nil {
+		t.Errorf("online event without capacity or venue was accepted")
+	}
+	events.CreateEvent(ctx, &models.CreateEventRequest{Title: "Webinar", Online: true, Capacity: 1000,
+		EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339)}, org)
+
+	alex := "52.5219,13.4132" // Alexanderplatz
+	for radius, want := range map[float64][]string{5: {"Berlin"}, 50: {"Berlin", "Potsdam"}, 300: {"Berlin", "Potsdam", "Hamburg"}} {
+		page, err := events.ListEvents(ctx, &models.EventListQuery{Near: alex, Radius: radius, Sort: "distance"})
+		if err != nil {
+			t.Fatalf("near %v km: %v", radius, err)
+		}
+		var got []string
+		for _, ev := range page.Events {
+			got = append(got, ev.Title)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("within %v km: got %v, want %v", radius, got, want)
+		}
+	}
+	page, _ := events.ListEvents(ctx, &models.EventListQuery{Near: alex, Radius: 50})
+	for _, ev := range page.Events {
+		if ev.Title == "Potsdam" && (ev.DistanceKm == nil || *ev.DistanceKm < 25 || *ev.DistanceKm > 30) {
+			t.Errorf("Potsdam distance = %v, want about 27 km", ev.DistanceKm)
+		}
+	}
+
+	online := true
+	if page, _ := events.ListEvents(ctx, &models.EventListQuery{Online: &online}); page.Total != 1 || page.Events[0].Title != "Webinar" {
+		t.Errorf("online filter: %+v", page.Events)
+	}
+	for _, bad := range []string{"/events?near=52.5", "/events?near=91,0", "/events?sort=distance"} {
+		if code, _ := listEvents(t, db, bad); code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", bad, code)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// newEventServices wires a BookingService and an EventService that share one
+// locker, as the server does.
+func newEventServices(db *gorm.DB, n notify.Notifier) (services.BookingService, services.EventService, repositories.RegistrationRepository) {
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	locker    := locking.NewMemoryLocker(5 * time.Second)
+	booking   := services.NewBookingService(db, regRepo, eventRepo, repositories.NewUserRepository(db), locker)
+	events    := services.NewEventService(db, eventRepo, regRepo,
+		repositories.NewTaxonomyRepository(db), repositories.NewVenueRepository(db),
+		repositories.NewTemplateRepository(db), locker, n)
+	return booking, events, regRepo
+}
+
+// recordingNotifier keeps every domain event it is sent.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, e notify.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+// TestUpdateEventCapacity checks ownership, rejects shrinking capacity below
+// the seats taken, and promotes the waitlist when capacity grows.
+func TestUpdateEventCapacity(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	booking, events, regRepo := newEventServices(db, notify.LogNotifier{})
+
+	org := createTestUser(t, db, 1)
+	a, b := createTestUser(t, db, 2), createTestUser(t, db, 3)
+	eventID := createTestEvent(t, db, org, 1)
+	booking.Book(ctx, a, eventID, nil)
+	if reg, _ := booking.Book(ctx, b, eventID, &models.BookingRequest{JoinWaitlist: true}); reg == nil || reg.Status != models.StatusWaitlisted {
+		t.Fatalf("second booking should be waitlisted, got %+v", reg)
+	}
+
+	title := "Renamed"
+	if _, err := events.UpdateEvent(ctx, a, eventID, &models.UpdateEventRequest{Title: &title}); err != services.ErrNotEventOrganizer {
+		t.Errorf("attendee editing: got %v, want ErrNotEventOrganizer", err)
+	}
+	zero := 0
+	if _, err := events.UpdateEvent(ctx, org, eventID, &models.UpdateEventRequest{Capacity: &zero}); !errors.Is(err, services.ErrCapacityBelowRegistered) {
+		t.Errorf("capacity 0: got %v, want ErrCapacityBelowRegistered", err)
+	}
+
+	two := 2
+	ev, err := events.UpdateEvent(ctx, org, eventID, &models.UpdateEventRequest{Title: &title, Capacity: &two})
+	if err != nil || ev.Title != title || ev.Capacity != 2 || ev.Registered != 2 {
+		t.Fatalf("update = %+v, %v; want renamed, capacity 2 with the waitlisted seat promoted", ev, err)
+	}
+	if reg, _ := regRepo.FindByUserAndEvent(ctx, b, eventID); reg == nil || reg.Status != models.StatusConfirmed {
+		t.Errorf("waitlisted user not promoted: %+v", reg)
+	}
+}
+
+// TestShrinkCapacityDuringBookings races capacity cuts against bookings and
+// checks the event is never left overbooked.
+func TestShrinkCapacityDuringBookings(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	booking, events, _ := newEventServices(db, notify.LogNotifier{})
+
+	org := createTestUser(t, db, 0)
+	eventID := createTestEvent(t, db, org, 20)
+	users := make([]string, 20)
+	for i := range users {
+		users[i] = createTestUser(t, db, i+1)
+	}
+
+	var wg sync.WaitGroup
+	for _, uid := range users {
+		wg.Add(1)
+		go func(uid string) {
+			defer wg.Done()
+			booking.Book(ctx, uid, eventID, nil)
+		}(uid)
+	}
+	for c := 19; c >= 5; c-- {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			events.UpdateEvent(ctx, org, eventID, &models.UpdateEventRequest{Capacity: &c})
+		}(c)
+	}
+	wg.Wait()
+
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	var confirmed int64
+	db.Model(&models.Registration{}).Where("event_id = ? AND status = ?", eventID, models.StatusConfirmed).Count(&confirmed)
+	if ev.Registered > ev.Capacity || int64(ev.Registered) != confirmed {
+		t.Fatalf("registered=%d capacity=%d confirmed=%d", ev.Registered, ev.Capacity, confirmed)
+	}
+}
+
+// TestDeleteEvent refuses to delete an event with live registrations and
+// hides it once deleted.
+func TestDeleteEvent(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	booking, events, _ := newEventServices(db, notify.LogNotifier{})
+
+	org := createTestUser(t, db, 1)
+	att := createTestUser(t, db, 2)
+	eventID := createTestEvent(t, db, org, 5)
+	booking.Book(ctx, att, eventID, nil)
+
+	if err := events.DeleteEvent(ctx, att, eventID); err != services.ErrNotEventOrganizer {
+		t.Errorf("attendee deleting: got %v, want ErrNotEventOrganizer", err)
+	}
+	if err := events.DeleteEvent(ctx, org, eventID); !errors.Is(err, services.ErrEventHasRegistrations) {
+		t.Errorf("delete with a booking: got %v, want ErrEventHasRegistrations", err)
+	}
+	booking.Cancel(ctx, att, eventID)
+	if err := events.DeleteEvent(ctx, org, eventID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := events.GetEvent(ctx, eventID); err == nil {
+		t.Error("deleted event is still returned")
+	}
+	if err := events.DeleteEvent(ctx, org, eventID); err != services.ErrEventNotFound {
+		t.Errorf("second delete: got %v, want ErrEventNotFound", err)
+	}
+}
+
+// TestCancelEventCascades cancels an event with confirmed, held and
+// waitlisted registrations and checks they are all cancelled with the reason,
+// the seats are released and one domain event lists them.
+func TestCancelEventCascades(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	rec := &recordingNotifier{}
+	booking, events, regRepo := newEventServices(db, rec)
+
+	org := createTestUser(t, db, 1)
+	a, b, c := createTestUser(t, db, 2), createTestUser(t, db, 3), createTestUser(t, db, 4)
+	eventID := createTestEvent(t, db, org, 2)
+	booking.Book(ctx, a, eventID, nil)
+	booking.Hold(ctx, b, eventID, nil)
+	booking.Book(ctx, c, eventID, &models.BookingRequest{JoinWaitlist: true})
+
+	if _, _, err := events.CancelEvent(ctx, a, eventID, "attendee try"); err != services.ErrNotEventOrganizer {
+		t.Errorf("attendee cancelling: got %v, want ErrNotEventOrganizer", err)
+	}
+	ev, n, err := events.CancelEvent(ctx, org, eventID, "Venue flooded")
+	if err != nil || n != 3 {
+		t.Fatalf("CancelEvent = %d, %v; want 3 registrations cancelled", n, err)
+	}
+	if ev.Status != models.EventCancelled || ev.CancelReason != "Venue flooded" || ev.Registered != 0 {
+		t.Errorf("event after cancel: status=%s reason=%q registered=%d", ev.Status, ev.CancelReason, ev.Registered)
+	}
+	for _, uid := range []string{a, b, c} {
+		if _, err := regRepo.FindByUserAndEvent(ctx, uid, eventID); err == nil {
+			t.Errorf("user %s still has an active registration", uid)
+		}
+	}
+	var reasons []string
+	db.Model(&models.RegistrationStatusChange{}).Where("to_status = ?", models.StatusCancelled).Pluck("reason", &reasons)
+	if len(reasons) != 3 || reasons[0] != "Venue flooded" {
+		t.Errorf("cancellation history reasons = %v", reasons)
+	}
+
+	if len(rec.events) != 1 {
+		t.Fatalf("notifier got %d events, want 1", len(rec.events))
+	}
+	msg, ok := rec.events[0].(notify.EventCancelled)
+	if !ok || msg.EventID != eventID || len(msg.Registrations) != 3 {
+		t.Errorf("domain event = %+v", rec.events[0])
+	}
+
+	if _, err := booking.Book(ctx, a, eventID, nil); err != services.ErrEventCancelled {
+		t.Errorf("booking a cancelled event: got %v, want ErrEventCancelled", err)
+	}
+	if _, _, err := events.CancelEvent(ctx, org, eventID, "again"); !errors.Is(err, services.ErrInvalidEventTransition) {
+		t.Errorf("second cancel: got %v, want ErrInvalidEventTransition", err)
+	}
+}
+
+// hookLocker runs before on the first Lock, ahead of taking the lock. before
+// may itself take locks.
+type hookLocker struct {
+	locking.Locker
+	mu     sync.Mutex
+	before func()
+}
+
+func (l *hookLocker) Lock(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	before := l.before
+	l.before = nil
+	l.mu.Unlock()
+	if before != nil {
+		before()
+	}
+	return l.Locker.Lock(ctx, key)
+}
+
+// TestCancelWhileBookingWaits cancels an event after a booking has checked
+// it but before the booking gets the lock; the booking must be refused.
+func TestCancelWhileBookingWaits(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	locker    := &hookLocker{Locker: locking.NewMemoryLocker(5 * time.Second)}
+	booking   := services.NewBookingService(db, regRepo, eventRepo, repositories.NewUserRepository(db), locker)
+	events    := services.NewEventService(db, eventRepo, regRepo,
+		repositories.NewTaxonomyRepository(db), repositories.NewVenueRepository(db),
+		repositories.NewTemplateRepository(db), locker, notify.LogNotifier{})
+
+	org, user := createTestUser(t, db, 1), createTestUser(t, db, 2)
+	eventID := createTestEvent(t, db, org, 5)
+	locker.before = func() {
+		if _, _, err := events.CancelEvent(ctx, org, eventID, "venue flooded"); err != nil {
+			t.Errorf("cancel: %v", err)
+		}
+	}
+
+	if _, err := booking.Book(ctx, user, eventID, nil); !errors.Is(err, services.ErrEventCancelled) {
+		t.Errorf("booking that waited out a cancel: got %v, want ErrEventCancelled", err)
+	}
+	ev, _ := eventRepo.FindByID(ctx, eventID)
+	if ev.Registered != 0 {
+		t.Errorf("registered = %d after a refused booking, want 0", ev.Registered)
+	}
+}
+
+// TestDraftEvents checks drafts are hidden from the list and closed to
+// bookings until published.
+func TestDraftEvents(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	booking, events, _ := newEventServices(db, notify.LogNotifier{})
+
+	org := createTestUser(t, db, 1)
+	att := createTestUser(t, db, 2)
+	draft, err := events.CreateEvent(ctx, &models.CreateEventRequest{
+		Title: "Secret launch", Capacity: 10, Status: "draft",
+		EventDate: time.Now().Add(48 * time.Hour).Format(time.RFC3339),
+	}, org)
+	if err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	if list, _ := events.ListEvents(ctx, &models.EventListQuery{}); list.Total != 0 {
+		t.Errorf("draft listed: %d events", list.Total)
+	}
+	if _, err := booking.Book(ctx, att, draft.ID, nil); err != services.ErrEventNotPublished {
+		t.Errorf("booking a draft: got %v, want ErrEventNotPublished", err)
+	}
+
+	if _, err := events.PublishEvent(ctx, org, draft.ID); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if list, _ := events.ListEvents(ctx, &models.EventListQuery{}); list.Total != 1 {
+		t.Errorf("published event not listed")
+	}
+	if _, err := booking.Book(ctx, att, draft.ID, nil); err != nil {
+		t.Errorf("booking after publish: %v", err)
+	}
+}
+
+// TestEventTimes covers local times across DST changes, offsets that don't
+// match the timezone, end times and the computed phase.
+func TestEventTimes(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	_, events, _ := newEventServices(db, notify.LogNotifier{})
+	org := createTestUser(t, db, 1)
+
+	create := func(start, end string) (*models.EventResponse, error) {
+		return events.CreateEvent(ctx, &models.CreateEventRequest{
+			Title: "Concert", Capacity: 10, EventDate: start, EndsAt: end, Timezone: "Europe/Berlin",
+		}, org)
+	}
+	for _, c := range []struct {
+		start string
+		want  error
+	}{
+		{"2027-03-28T02:30", services.ErrNonexistentTime},         // clocks jump 02:00 → 03:00
+		{"2027-10-31T02:30", services.ErrAmbiguousTime},           // 02:00–03:00 happens twice
+		{"2027-07-01T19:00:00+01:00", services.ErrOffsetMismatch}, // Berlin is +02:00 in July
+	} {
+		if _, err := create(c.start, ""); !errors.Is(err, c.want) || !errors.Is(err, services.ErrInvalidEvent) {
+			t.Errorf("start %s: got %v, want %v (an ErrInvalidEvent)", c.start, err, c.want)
+		}
+	}
+	if _, err := create("2027-10-31T02:30:00+01:00", ""); err != nil {
+		t.Errorf("explicit offset in the repeated hour: %v", err)
+	}
+	if _, err := create("2027-07-01T19:00", "2027-07-01T18:00"); err != services.ErrEndBeforeStart {
+		t.Errorf("end before start: got %v", err)
+	}
+
+	ev, err := create("2027-07-01T19:00", "")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if got := ev.EventDate.UTC().Format(time.RFC3339); got != "2027-07-01T17:00:00Z" {
+		t.Errorf("start = %s, want 17:00Z", got)
+	}
+	if ev.LocalStart != "2027-07-01T19:00:00+02:00" || ev.LocalEnd != "2027-07-01T21:00:00+02:00" {
+		t.Errorf("local times = %s – %s", ev.LocalStart, ev.LocalEnd)
+	}
+	if ev.Phase != models.PhaseUpcoming {
+		t.Errorf("phase = %s, want upcoming", ev.Phase)
+	}
+	early := "2027-07-01T18:30"
+	if _, err := events.UpdateEvent(ctx, org, ev.ID, &models.UpdateEventRequest{EndsAt: &early}); err != services.ErrEndBeforeStart {
+		t.Errorf("moving the end before the start: got %v", err)
+	}
+
+	now := time.Now().UTC()
+	live, err := create(now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339))
+	if err != nil || live.Phase != models.PhaseOngoing {
+		t.Errorf("running event: phase %v, err %v", live.Phase, err)
+	}
+	if p := (&models.Event{EventDate: now.Add(-3 * time.Hour)}).PhaseAt(now); p != models.PhasePast {
+		t.Errorf("event without an end, started 3h ago: phase %s, want past", p)
+	}
+}
+
+// TestCloneEventAndTemplates clones an event with bookings to a new date and
+// creates events from a saved template.
+func TestCloneEventAndTemplates(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	booking, events, _ := newEventServices(db, notify.LogNotifier{})
+	templates := services.NewTemplateService(repositories.NewTemplateRepository(db), repositories.NewEventRepository(db))
+	org, rival, att := createTestUser(t, db, 1), createTestUser(t, db, 2), createTestUser(t, db, 3)
+
+	src, err := events.CreateEvent(ctx, &models.CreateEventRequest{
+		Title: "Go Meetup", Description: "Monthly talks", Capacity: 40,
+		EventDate: "2027-01-14T19:00", EndsAt: "2027-01-14T22:00", Timezone: "Europe/Berlin",
+		SalesOpenAt: "2026-10-01T09:00",
+		Tiers: []models.TierRequest{{Name: "Standard", Capacity: 30}, {Name: "Speaker", Capacity: 10, SalesEndAt: "2027-01-07T00:00"}},
+		Tags:  []string{"golang"},
+	}, org)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := booking.Book(ctx, att, src.ID, &models.BookingRequest{TierID: src.Tiers[0].ID}); err != nil {
+		t.Fatalf("book: %v", err)
+	}
+
+	if _, err := events.CloneEvent(ctx, rival, src.ID, &models.CloneEventRequest{EventDate: "2027-02-11T19:00"}); err != services.ErrNotEventOrganizer {
+		t.Errorf("clone by another organizer: got %v, want ErrNotEventOrganizer", err)
+	}
+	cp, err := events.CloneEvent(ctx, org, src.ID, &models.CloneEventRequest{EventDate: "2027-02-11T19:00", Status: "draft"})
+	if err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	if cp.ID == src.ID || cp.Title != "Go Meetup" || cp.Registered != 0 || cp.Status != models.EventDraft || len(cp.Tiers) != 2 {
+		t.Fatalf("clone = %+v, want a new empty draft with both tiers", cp.Event)
+	}
+	if cp.LocalStart != "2027-02-11T19:00:00+01:00" || cp.LocalEnd != "2027-02-11T22:00:00+01:00" {
+		t.Errorf("clone times = %s – %s, want 19:00–22:00 local", cp.LocalStart, cp.LocalEnd)
+	}
+	if want := src.SalesOpenAt.Add(28 * 24 * time.Hour); cp.SalesOpenAt == nil || !cp.SalesOpenAt.Equal(want) {
+		t.Errorf("clone sales open = %v, want %v", cp.SalesOpenAt, want)
+	}
+	for _, tier := range cp.Tiers {
+		if tier.Registered != 0 || tier.EventID != cp.ID {
+			t.Errorf("cloned tier %+v carries seats or points at the source", tier)
+		}
+	}
+	if len(cp.Tags) != 1 || cp.Tags[0].Name != "golang" {
+		t.Errorf("clone tags = %v", cp.Tags)
+	}
+	var copied int64
+	db.Model(&models.Registration{}).Where("event_id = ?", cp.ID).Count(&copied)
+	if copied != 0 {
+		t.Errorf("clone has %d registrations, want none", copied)
+	}
+
+	tpl, err := templates.CreateTemplate(ctx, org, &models.CreateTemplateRequest{Name: "Meetup", EventID: src.ID, Capacity: 50})
+	if err != nil {
+		t.Fatalf("template from event: %v", err)
+	}
+	if tpl.Title != "Go Meetup" || tpl.Capacity != 50 || len(tpl.Tiers) != 2 || tpl.Tiers[0].Name == tpl.Tiers[1].Name {
+		t.Errorf("template = %+v, want the event's title and tiers with capacity 50", tpl)
+	}
+	if _, err := templates.CreateTemplate(ctx, org, &models.CreateTemplateRequest{Name: "Meetup", Title: "Other"}); err != services.ErrTemplateExists {
+		t.Errorf("duplicate name: got %v, want ErrTemplateExists", err)
+	}
+	// Two saves racing past the name check: the loser hits the unique index.
+	racer := &models.EventTemplate{OrganizerID: org, Name: "Meetup", Title: "Racer"}
+	if err := repositories.NewTemplateRepository(db).Create(ctx, racer); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("duplicate insert: got %v, want gorm.ErrDuplicatedKey", err)
+	}
+	if _, err := templates.CreateTemplate(ctx, org, &models.CreateTemplateRequest{Name: "   ", Title: "Blank"}); err == nil {
+		t.Error("blank template name was accepted")
+	}
+	if _, err := templates.CreateTemplate(ctx, rival, &models.CreateTemplateRequest{Name: "Stolen", EventID: src.ID}); err != services.ErrNotEventOrganizer {
+		t.Errorf("template from another's event: got %v, want ErrNotEventOrganizer", err)
+	}
+
+	ev, err := events.CreateEvent(ctx, &models.CreateEventRequest{TemplateID: tpl.ID, EventDate: "2027-03-11T18:00:00Z"}, org)
+	if err != nil {
+		t.Fatalf("create from template: %v", err)
+	}
+	if ev.Title != "Go Meetup" || ev.Description != "Monthly talks" || ev.Capacity != 50 || len(ev.Tiers) != 2 {
+		t.Errorf("event from template = %+v", ev.Event)
+	}
+	ev, err = events.CreateEvent(ctx, &models.CreateEventRequest{TemplateID: tpl.ID, Title: "Go Meetup: Special", Capacity: 20,
+		Tiers: []models.TierRequest{{Name: "General", Capacity: 20}}, EventDate: "2027-04-08T18:00:00Z"}, org)
+	if err != nil || ev.Title != "Go Meetup: Special" || ev.Capacity != 20 || len(ev.Tiers) != 1 {
+		t.Errorf("request fields should win over the template: %+v, %v", ev, err)
+	}
+	if _, err := events.CreateEvent(ctx, &models.CreateEventRequest{TemplateID: tpl.ID, EventDate: "2027-03-11T18:00:00Z"}, rival); err != services.ErrTemplateNotFound {
+		t.Errorf("another organizer's template: got %v, want ErrTemplateNotFound", err)
+	}
+	if err := templates.DeleteTemplate(ctx, org, tpl.ID); err != nil {
+		t.Fatalf("delete template: %v", err)
+	}
+	if list, _ := templates.ListTemplates(ctx, org); len(list) != 0 {
+		t.Errorf("templates after delete = %d, want 0", len(list))
+	}
+}
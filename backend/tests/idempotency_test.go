@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+)
+
+// TestIdempotencyReplay verifies retries with the same key replay the first
+// response, and that reusing the key with a different body is rejected.
+func TestIdempotencyReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB(t)
+
+	var calls int32
+	r := gin.New()
+	r.POST("/things",
+		func(c *gin.Context) { c.Set(middleware.ContextKeyUserID, "user-1") },
+		middleware.Idempotency(repositories.NewIdempotencyRepository(db)),
+		func(c *gin.Context) {
+			n := atomic.AddInt32(&calls, 1)
+			c.JSON(http.StatusCreated, gin.H{"call": n})
+		},
+	)
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("k1", `{"a":1}`)
+	retry := send("k1", `{"a":1}`)
+	if first.Code != http.StatusCreated || retry.Code != http.StatusCreated {
+		t.Fatalf("codes = %d, %d; want 201, 201", first.Code, retry.Code)
+	}
+	if retry.Body.String() != first.Body.String() || retry.Header().Get(middleware.HeaderIdempotentReplay) != "true" {
+		t.Errorf("retry was not a replay: %q vs %q", retry.Body.String(), first.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	if w := send("k1", `{"a":2}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("key reused with a different body: got %d, want 422", w.Code)
+	}
+	if w := send("k2", `{"a":2}`); w.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("fresh key: got %d after %d calls", w.Code, calls)
+	}
+}
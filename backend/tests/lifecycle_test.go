@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// TestRegistrationLifecycle walks registrations through cancel, check-in,
+// no-show and refund, checking that illegal moves are rejected, seats follow
+// the status and every change is recorded with its actor.
+func TestRegistrationLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	booking   := newBookingService(db, regRepo, eventRepo)
+	tickets   := services.NewTicketService(db, regRepo, eventRepo)
+
+	org := createTestUser(t, db, 1)
+	gone, late, absent := createTestUser(t, db, 2), createTestUser(t, db, 3), createTestUser(t, db, 4)
+	eventID := createTestEvent(t, db, org, 5)
+
+	cancelled, _ := booking.Book(ctx, gone, eventID, nil)
+	lateReg, _ := booking.Book(ctx, late, eventID, nil)
+	absentReg, _ := booking.Book(ctx, absent, eventID, nil)
+	ticket, err := tickets.Ticket(ctx, gone, cancelled.ID)
+	if err != nil {
+		t.Fatalf("ticket: %v", err)
+	}
+	if _, err := booking.Cancel(ctx, gone, eventID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := tickets.CheckIn(ctx, org, eventID, ticket.Token); err != services.ErrInvalidTicket {
+		t.Errorf("checking in a cancelled ticket: got %v, want ErrInvalidTicket", err)
+	}
+
+	if _, err := tickets.MarkNoShows(ctx, org, eventID); err != services.ErrEventNotStarted {
+		t.Errorf("no-shows before the event: got %v, want ErrEventNotStarted", err)
+	}
+	db.Model(&models.Event{}).Where("id = ?", eventID).Update("event_date", time.Now().Add(-time.Hour))
+	if n, err := tickets.MarkNoShows(ctx, org, eventID); err != nil || n != 2 {
+		t.Fatalf("MarkNoShows = %d, %v; want 2, nil", n, err)
+	}
+	lateTicket, _ := tickets.Ticket(ctx, late, lateReg.ID)
+	if reg, err := tickets.CheckIn(ctx, org, eventID, lateTicket.Token); err != nil || reg.Status != models.StatusCheckedIn {
+		t.Fatalf("late check-in = %+v, %v", reg, err)
+	}
+	if _, err := booking.Cancel(ctx, absent, eventID); !errors.Is(err, services.ErrInvalidTransition) {
+		t.Errorf("cancelling a no-show: got %v, want ErrInvalidTransition", err)
+	}
+
+	if _, err := booking.Refund(ctx, absent, absentReg.ID); err != services.ErrNotEventOrganizer {
+		t.Errorf("attendee refunding: got %v, want ErrNotEventOrganizer", err)
+	}
+	for _, id := range []string{cancelled.ID, absentReg.ID} {
+		if reg, err := booking.Refund(ctx, org, id); err != nil || reg.Status != models.StatusRefunded {
+			t.Fatalf("refund %s = %+v, %v", id, reg, err)
+		}
+	}
+	if _, err := booking.Refund(ctx, org, absentReg.ID); !errors.Is(err, services.ErrInvalidTransition) {
+		t.Errorf("second refund: got %v, want ErrInvalidTransition", err)
+	}
+
+	var ev models.Event
+	db.First(&ev, "id = ?", eventID)
+	if ev.Registered != 1 {
+		t.Errorf("registered = %d, want 1 (only the checked-in seat)", ev.Registered)
+	}
+
+	var history []models.RegistrationStatusChange
+	db.Where("registration_id = ?", absentReg.ID).Order("created_at asc").Find(&history)
+	want := []models.RegistrationStatus{models.StatusConfirmed, models.StatusNoShow, models.StatusRefunded}
+	if len(history) != len(want) {
+		t.Fatalf("history has %d entries, want %d", len(history), len(want))
+	}
+	for i, h := range history {
+		if h.ToStatus != want[i] {
+			t.Errorf("history[%d] = %s, want %s", i, h.ToStatus, want[i])
+		}
+	}
+	if a := history[0].ActorID; a == nil || *a != absent {
+		t.Errorf("booking actor = %v, want the attendee", a)
+	}
+	if a := history[2].ActorID; a == nil || *a != org {
+		t.Errorf("refund actor = %v, want the organizer", a)
+	}
+}
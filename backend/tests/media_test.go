@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/handlers"
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/notify"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+	"github.com/Amrutavarshini24/Eventregistration/internal/storage"
+)
+
+// testJPEG encodes a w × h gradient.
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// countFiles counts the regular files under dir.
+func countFiles(t *testing.T, dir string) int {
+	t.Helper()
+	n := 0
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+// TestEventMedia uploads, replaces, serves and deletes event images, and
+// rejects non-images, oversize files and uploads by other users.
+func TestEventMedia(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	t.Setenv("MEDIA_MAX_BYTES", "1000000")
+	dir := t.TempDir()
+	blobs, err := storage.NewLocalStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	media := services.NewMediaService(db, repositories.NewEventRepository(db), repositories.NewMediaRepository(db),
+		blobs, locking.NewMemoryLocker(5*time.Second))
+	_, events, _ := newEventServices(db, notify.LogNotifier{})
+
+	org, other := createTestUser(t, db, 1), createTestUser(t, db, 2)
+	eventID := createTestEvent(t, db, org, 10)
+	big := testJPEG(t, 2000, 1000)
+
+	if _, err := media.Upload(ctx, other, eventID, models.MediaCover, bytes.NewReader(big)); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("upload by non-organizer: got %v, want ErrNotEventOrganizer", err)
+	}
+	if _, err := media.Upload(ctx, org, eventID, models.MediaGallery, strings.NewReader("<svg onload=alert(1)>")); !errors.Is(err, services.ErrUnsupportedMedia) {
+		t.Errorf("non-image upload: got %v, want ErrUnsupportedMedia", err)
+	}
+	if _, err := media.Upload(ctx, org, eventID, models.MediaGallery, bytes.NewReader(make([]byte, 1000001))); !errors.Is(err, services.ErrMediaTooLarge) {
+		t.Errorf("oversize upload: got %v, want ErrMediaTooLarge", err)
+	}
+	// Over HTTP the body limit trips while the form is parsed, even with kind
+	// sent before the file.
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("kind", "gallery")
+	fw, _ := mw.CreateFormFile("file", "huge.jpg")
+	fw.Write(make([]byte, 3000000))
+	mw.Close()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/events/:id/media", func(c *gin.Context) { c.Set(middleware.ContextKeyUserID, org) },
+		handlers.NewMediaHandler(media).UploadMedia)
+	req := httptest.NewRequest(http.MethodPost, "/events/"+eventID+"/media", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversize HTTP upload: got %d %s, want 413", w.Code, w.Body.String())
+	}
+	if n := countFiles(t, dir); n != 0 {
+		t.Fatalf("rejected uploads left %d files", n)
+	}
+
+	first, err := media.Upload(ctx, org, eventID, models.MediaCover, bytes.NewReader(big))
+	if err != nil {
+		t.Fatalf("cover upload: %v", err)
+	}
+	if first.Width != 2000 || first.Height != 1000 || first.ContentType != "image/jpeg" {
+		t.Errorf("cover = %+v, want a 2000x1000 JPEG", first.EventMedia)
+	}
+	rc, ct, err := media.Open(ctx, eventID, first.ID, "thumb")
+	if err != nil {
+		t.Fatalf("open thumb: %v", err)
+	}
+	thumb, _, err := image.DecodeConfig(rc)
+	rc.Close()
+	if err != nil || ct != "image/jpeg" || thumb.Width != models.ThumbImageSide || thumb.Height != models.ThumbImageSide/2 {
+		t.Errorf("thumb = %dx%d %s (%v), want %dx%d image/jpeg", thumb.Width, thumb.Height, ct, err, models.ThumbImageSide, models.ThumbImageSide/2)
+	}
+	if _, _, err := media.Open(ctx, eventID, first.ID, "../original"); !errors.Is(err, services.ErrMediaNotFound) {
+		t.Errorf("unknown variant: got %v, want ErrMediaNotFound", err)
+	}
+
+	// A small image has no copies; its original serves every variant.
+	small, err := media.Upload(ctx, org, eventID, models.MediaGallery, bytes.NewReader(testJPEG(t, 100, 80)))
+	if err != nil {
+		t.Fatalf("gallery upload: %v", err)
+	}
+	rc, _, err = media.Open(ctx, eventID, small.ID, "medium")
+	if err != nil {
+		t.Fatalf("open small medium: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if cfg, _, _ := image.DecodeConfig(bytes.NewReader(got)); cfg.Width != 100 {
+		t.Errorf("small medium width = %d, want the original's 100", cfg.Width)
+	}
+
+	// Replacing the cover removes the old one and its files.
+	second, err := media.Upload(ctx, org, eventID, models.MediaCover, bytes.NewReader(big))
+	if err != nil {
+		t.Fatalf("cover replace: %v", err)
+	}
+	if _, _, err := media.Open(ctx, eventID, first.ID, "original"); !errors.Is(err, services.ErrMediaNotFound) {
+		t.Errorf("replaced cover still served: %v", err)
+	}
+	if n := countFiles(t, dir); n != 4 {
+		t.Errorf("files after replace = %d, want 4 (3 cover + 1 small gallery)", n)
+	}
+
+	ev, err := events.GetEvent(ctx, eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Cover == nil || ev.Cover.ID != second.ID || len(ev.Gallery) != 1 || ev.Gallery[0].ID != small.ID {
+		t.Errorf("event cover/gallery = %+v / %+v, want the new cover and one gallery image", ev.Cover, ev.Gallery)
+	}
+	if want := "/api/events/" + eventID + "/media/" + second.ID + "/thumb"; ev.Cover != nil && ev.Cover.ThumbnailURL != want {
+		t.Errorf("thumbnail url = %q, want %q", ev.Cover.ThumbnailURL, want)
+	}
+
+	if err := media.Delete(ctx, other, eventID, small.ID); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("delete by non-organizer: got %v, want ErrNotEventOrganizer", err)
+	}
+	if err := media.Delete(ctx, org, eventID, small.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := media.Delete(ctx, org, eventID, small.ID); !errors.Is(err, services.ErrMediaNotFound) {
+		t.Errorf("second delete: got %v, want ErrMediaNotFound", err)
+	}
+	if n := countFiles(t, dir); n != 3 {
+		t.Errorf("files after delete = %d, want 3", n)
+	}
+}
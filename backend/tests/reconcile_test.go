@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/locking"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// TestSeatReconciler corrupts the event and tier counters, then checks a dry
+// run reports the drift without touching it and a real run repairs it.
+func TestSeatReconciler(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	booking   := newBookingService(db, regRepo, eventRepo)
+	rec       := services.NewSeatReconciler(db, regRepo, eventRepo, locking.NewMemoryLocker(5*time.Second))
+
+	org := createTestUser(t, db, 1)
+	ev := &models.Event{
+		Title: "Drifty", Capacity: 10, EventDate: time.Now().Add(24 * time.Hour), OrganizerID: org,
+		Tiers: []models.TicketTier{{Name: "General", Capacity: 10}},
+	}
+	db.Create(ev)
+	tierID := ev.Tiers[0].ID
+	for i := 2; i <= 4; i++ {
+		if _, err := booking.Book(ctx, createTestUser(t, db, i), ev.ID, &models.BookingRequest{TierID: tierID}); err != nil {
+			t.Fatalf("book: %v", err)
+		}
+	}
+	createTestEvent(t, db, org, 5) // a second, untouched event
+
+	if rep, err := rec.Reconcile(ctx, false); err != nil || len(rep.Drift) != 0 || rep.EventsChecked != 2 {
+		t.Fatalf("consistent tree: report %+v, %v", rep, err)
+	}
+
+	db.Model(&models.Event{}).Where("id = ?", ev.ID).Update("registered", 7)
+	db.Model(&models.TicketTier{}).Where("id = ?", tierID).Update("registered", 0)
+
+	rep, err := rec.Reconcile(ctx, true)
+	if err != nil || len(rep.Drift) != 2 {
+		t.Fatalf("dry run: report %+v, %v; want 2 drifted counters", rep, err)
+	}
+	if d := rep.Drift[0]; d.TierID != nil || d.Stored != 7 || d.Actual != 3 {
+		t.Errorf("event drift = %+v, want stored 7 actual 3", d)
+	}
+	var stored models.Event
+	db.First(&stored, "id = ?", ev.ID)
+	if stored.Registered != 7 {
+		t.Errorf("dry run changed the counter to %d", stored.Registered)
+	}
+
+	if rep, err = rec.Reconcile(ctx, false); err != nil || len(rep.Drift) != 2 {
+		t.Fatalf("fix run: report %+v, %v", rep, err)
+	}
+	var fixed models.Event
+	db.Preload("Tiers").First(&fixed, "id = ?", ev.ID)
+	if fixed.Registered != 3 || fixed.Tiers[0].Registered != 3 {
+		t.Errorf("after fix: event %d tier %d, want 3 and 3", fixed.Registered, fixed.Tiers[0].Registered)
+	}
+	if rep, _ = rec.Reconcile(ctx, true); len(rep.Drift) != 0 {
+		t.Errorf("drift remains after fix: %+v", rep.Drift)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// TestOrganizerStats books, cancels and checks in across two events and
+// checks the dashboard's counts, rates, sell-out time and timeline.
+func TestOrganizerStats(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	booking   := newBookingService(db, regRepo, eventRepo)
+	tickets   := services.NewTicketService(db, regRepo, eventRepo)
+	stats     := services.NewStatsService(eventRepo, repositories.NewStatsRepository(db))
+
+	org, rival := createTestUser(t, db, 1), createTestUser(t, db, 2)
+	soldOut := createTestEvent(t, db, org, 2)
+	open    := createTestEvent(t, db, org, 10)
+	createTestEvent(t, db, rival, 5)
+	users := make([]string, 5)
+	for i := range users {
+		users[i] = createTestUser(t, db, 10+i)
+	}
+
+	booking.Book(ctx, users[0], soldOut, nil)
+	booking.Book(ctx, users[1], soldOut, nil)
+	booking.Book(ctx, users[2], soldOut, &models.BookingRequest{JoinWaitlist: true})
+	group := &models.BookingRequest{Quantity: 2, Attendees: []models.AttendeeRequest{{Name: "Ann Lee"}, {Name: "Bo Kim"}}}
+	reg, err := booking.Book(ctx, users[0], open, group)
+	if err != nil {
+		t.Fatalf("group booking: %v", err)
+	}
+	booking.Book(ctx, users[1], open, nil)
+	booking.Book(ctx, users[2], open, nil)
+	if _, err := booking.Cancel(ctx, users[2], open); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	ticket, err := tickets.Ticket(ctx, users[0], reg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tickets.CheckIn(ctx, org, open, ticket.Token); err != nil {
+		t.Fatalf("check-in: %v", err)
+	}
+
+	all, err := stats.OrganizerStats(ctx, org, &models.StatsQuery{})
+	if err != nil {
+		t.Fatalf("organizer stats: %v", err)
+	}
+	if len(all.Events) != 2 {
+		t.Fatalf("got %d events, want the organizer's 2", len(all.Events))
+	}
+	byID := map[string]models.EventStats{}
+	for _, ev := range all.Events {
+		byID[ev.EventID] = ev
+	}
+
+	full := byID[soldOut]
+	if full.SeatsSold != 2 || full.Waitlisted != 1 || full.SellThroughPct != 100 || full.Bookings != 2 {
+		t.Errorf("sold-out event = %+v, want 2 sold, 1 waitlisted, 100%%, 2 bookings", full)
+	}
+	if full.SoldOutAt == nil || full.TimeToSellOutSeconds == nil || *full.TimeToSellOutSeconds < 0 {
+		t.Errorf("sold-out event has no sell-out time: %+v", full)
+	}
+
+	part := byID[open]
+	if part.SeatsSold != 3 || part.SellThroughPct != 30 || part.Bookings != 3 || part.Cancellations != 1 || part.CancelledSeats != 1 {
+		t.Errorf("open event = %+v, want 3 sold (30%%), 3 bookings, 1 cancellation", part)
+	}
+	if part.CheckedIn != 2 || part.CheckInRatePct != 66.7 || part.SoldOutAt != nil {
+		t.Errorf("open event check-in = %d seats (%.1f%%), sold out %v; want 2 (66.7%%), not sold out",
+			part.CheckedIn, part.CheckInRatePct, part.SoldOutAt)
+	}
+
+	if tot := all.Totals; tot.Events != 2 || tot.Capacity != 12 || tot.SeatsSold != 5 || tot.SoldOut != 1 || tot.Cancellations != 1 {
+		t.Errorf("totals = %+v", tot)
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if len(all.Timeline) != 1 || !all.Timeline[0].Start.Equal(today) ||
+		all.Timeline[0].Bookings != 5 || all.Timeline[0].Seats != 6 || all.Timeline[0].Cancellations != 1 {
+		t.Errorf("day timeline = %+v, want one bucket at %s with 5 bookings, 6 seats, 1 cancellation", all.Timeline, today)
+	}
+
+	one, err := stats.EventStats(ctx, org, open, &models.StatsQuery{Bucket: "hour"})
+	if err != nil {
+		t.Fatalf("event stats: %v", err)
+	}
+	hour := time.Now().UTC().Truncate(time.Hour)
+	if len(one.Timeline) == 0 || one.Timeline[len(one.Timeline)-1].Start.After(hour) || one.Bucket != "hour" {
+		t.Errorf("hour timeline = %+v", one.Timeline)
+	}
+	later := &models.StatsQuery{From: time.Now().Add(time.Hour)}
+	if one, _ := stats.EventStats(ctx, org, open, later); one == nil || len(one.Timeline) != 0 || one.SeatsSold != 3 {
+		t.Errorf("from= should empty the timeline but keep the totals: %+v", one)
+	}
+	if _, err := stats.EventStats(ctx, rival, open, &models.StatsQuery{}); !errors.Is(err, services.ErrNotEventOrganizer) {
+		t.Errorf("another organizer's event: got %v, want ErrNotEventOrganizer", err)
+	}
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Amrutavarshini24/Eventregistration/internal/middleware"
+	"github.com/Amrutavarshini24/Eventregistration/internal/models"
+	"github.com/Amrutavarshini24/Eventregistration/internal/repositories"
+	"github.com/Amrutavarshini24/Eventregistration/internal/services"
+)
+
+// TestTicketCheckIn verifies a signed ticket checks in once, that a second
+// scan is rejected and that a tampered token never verifies.
+func TestTicketCheckIn(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	booking   := newBookingService(db, regRepo, eventRepo)
+	tickets   := services.NewTicketService(db, regRepo, eventRepo)
+
+	org := &models.User{Name: "Org", Email: "org9@t.com", PasswordHash: "h", Role: "organizer"}
+	db.Create(org)
+	eventID := createTestEvent(t, db, org.ID, 5)
+	att := createTestUser(t, db, 1)
+
+	reg, err := booking.Book(ctx, att, eventID, nil)
+	if err != nil {
+		t.Fatalf("booking failed: %v", err)
+	}
+	ticket, err := tickets.Ticket(ctx, att, reg.ID)
+	if err != nil {
+		t.Fatalf("ticket: %v", err)
+	}
+	png, err := tickets.TicketQR(ctx, att, reg.ID, 128)
+	if err != nil || !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Fatalf("QR code is not a PNG: %v", err)
+	}
+
+	tampered := ticket.Token[:len(ticket.Token)-2] + "xx"
+	if _, err := tickets.CheckIn(ctx, org.ID, eventID, tampered); err != services.ErrInvalidTicket {
+		t.Errorf("tampered token: got %v, want ErrInvalidTicket", err)
+	}
+	if _, err := tickets.CheckIn(ctx, att, eventID, ticket.Token); err != services.ErrNotEventOrganizer {
+		t.Errorf("attendee scanning: got %v, want ErrNotEventOrganizer", err)
+	}
+	checked, err := tickets.CheckIn(ctx, org.ID, eventID, ticket.Token)
+	if err != nil || checked.Status != models.StatusCheckedIn || checked.CheckedInAt == nil {
+		t.Fatalf("check-in = %+v, %v", checked, err)
+	}
+	if _, err := tickets.CheckIn(ctx, org.ID, eventID, ticket.Token); err != services.ErrAlreadyCheckedIn {
+		t.Errorf("second scan: got %v, want ErrAlreadyCheckedIn", err)
+	}
+}
+
+// TestTicketTokenIsNotLogin sends ticket tokens as Bearer tokens and expects
+// 401 rather than a login (or a panic), while a real login token gets in.
+func TestTicketTokenIsNotLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repositories.NewEventRepository(db)
+	regRepo   := repositories.NewRegistrationRepository(db)
+	booking   := newBookingService(db, regRepo, eventRepo)
+	tickets   := services.NewTicketService(db, regRepo, eventRepo)
+	auth      := services.NewAuthService(repositories.NewUserRepository(db))
+
+	if _, err := auth.Register(ctx, &models.RegisterRequest{Name: "Ann", Email: "ann@t.com", Password: "secret1"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	login, user, err := auth.Login(ctx, &models.LoginRequest{Email: "ann@t.com", Password: "secret1"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	reg, err := booking.Book(ctx, user.ID, createTestEvent(t, db, createTestUser(t, db, 1), 5), nil)
+	if err != nil {
+		t.Fatalf("booking failed: %v", err)
+	}
+	ticket, err := tickets.Ticket(ctx, user.ID, reg.ID)
+	if err != nil {
+		t.Fatalf("ticket: %v", err)
+	}
+	// A ticket-shaped token signed with the login key, as tickets once were.
+	legacy, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ": "ticket", "sub": reg.ID, "role": "organizer",
+	}).SignedString([]byte("dev_secret_please_change"))
+
+	r := gin.New()
+	r.GET("/me", middleware.AuthRequired(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := get(login); code != http.StatusOK {
+		t.Errorf("login token: got %d, want 200", code)
+	}
+	if code := get(ticket.Token); code != http.StatusUnauthorized {
+		t.Errorf("ticket token: got %d, want 401", code)
+	}
+	if code := get(legacy); code != http.StatusUnauthorized {
+		t.Errorf("ticket-typed token: got %d, want 401", code)
+	}
+}
@@ -29,7 +29,18 @@ async function apiFetch(path, options = {}) {
 const api = {
   register: (body) => apiFetch('/auth/register', { method: 'POST', body: JSON.stringify(body) }),
   login: (body) => apiFetch('/auth/login', { method: 'POST', body: JSON.stringify(body) }),
-  listEvents: () => apiFetch('/events'),
+  // Follows the list's `next` links so every event is loaded, not just the
+  // first page.
+  listEvents: async () => {
+    const events = [];
+    let path = '/events?limit=100'; // the largest page the API serves
+    while (path) {
+      const page = await apiFetch(path);
+      events.push(...(page.events || []));
+      path = page.next ? page.next.replace(/^\/api/, '') : null;
+    }
+    return { events };
+  },
   getEvent: (id) => apiFetch(`/events/${id}`),
   createEvent: (body) => apiFetch('/events', { method: 'POST', body: JSON.stringify(body) }),
   bookEvent: (id) => apiFetch(`/events/${id}/register`, { method: 'POST' }),